@@ -14,6 +14,7 @@ type SoundManager struct {
 	audioContext *audio.Context
 	sounds       map[string]*audio.Player
 	looping      map[string]*audio.Player // for looping sounds
+	volume       float64
 }
 
 func NewSoundManager() *SoundManager {
@@ -22,6 +23,18 @@ func NewSoundManager() *SoundManager {
 		audioContext: ctx,
 		sounds:       make(map[string]*audio.Player),
 		looping:      make(map[string]*audio.Player),
+		volume:       1,
+	}
+}
+
+// SetVolume sets the volume (0 to 1) for all current and future sounds.
+func (sm *SoundManager) SetVolume(volume float64) {
+	sm.volume = volume
+	for _, player := range sm.sounds {
+		player.SetVolume(volume)
+	}
+	for _, player := range sm.looping {
+		player.SetVolume(volume)
 	}
 }
 
@@ -34,11 +47,17 @@ func (sm *SoundManager) LoadSound(name string, data []byte) error {
 	if err != nil {
 		return err
 	}
+	player.SetVolume(sm.volume)
 	sm.sounds[name] = player
 	return nil
 }
 
+// PlaySound plays a loaded sound from the start. A nil SoundManager stays silent,
+// which lets the game simulate without audio.
 func (sm *SoundManager) PlaySound(name string) {
+	if sm == nil {
+		return
+	}
 	if player, ok := sm.sounds[name]; ok {
 		log.Printf("PlaySound: playing %s", name)
 		player.Rewind()
@@ -65,6 +84,7 @@ func (sm *SoundManager) LoadLoopingSound(name string, data []byte) error {
 	if err != nil {
 		return err
 	}
+	player.SetVolume(sm.volume)
 	sm.looping[name] = player
 	return nil
 }
@@ -78,6 +98,9 @@ func (sm *SoundManager) PlayLoopingSound(name string) {
 }
 
 func (sm *SoundManager) PauseLoopingSound(name string) {
+	if sm == nil {
+		return
+	}
 	if player, ok := sm.looping[name]; ok {
 		player.Pause()
 	} else {
@@ -2,22 +2,71 @@ package core
 
 import (
 	"image"
+	"snakeGame/game/entities"
 )
 
-func (g *Game) checkCollision(newHead image.Point) bool {
+// deathCause is what ended a run, as counted in the lifetime statistics.
+type deathCause string
+
+const (
+	causeNone     deathCause = ""
+	causeWall     deathCause = "wall"     // the edge of the board
+	causeSelf     deathCause = "self"     // the snake's own body
+	causeObstacle deathCause = "obstacle" // hazards, map walls and enemies
+	causePoison   deathCause = "poison"   // eating poison
+)
+
+// deathCauses lists every cause in the order the statistics show them.
+var deathCauses = []deathCause{causeWall, causeSelf, causeObstacle, causePoison}
+
+// checkCollision returns what a head moving to newHead crashes into, or causeNone.
+func (g *Game) checkCollision(newHead image.Point) deathCause {
 	// Check out-of-bounds
-	outOfBounds := newHead.X < 0 || newHead.X >= g.gridWidth || newHead.Y < 0 || newHead.Y >= g.gridHeight
+	outOfBounds := g.outOfBounds(newHead)
 
 	// Determine if the snake is growing (e.g., head touches food)
-	growing := newHead == g.Food.Pos
+	growing := false
+	if food := g.foodAt(newHead); food != nil {
+		growing = food.Kind == entities.FoodApple || food.Kind == entities.FoodGoldenApple
+	}
 
 	// Get current tail
 	tail := g.Snake.Tail
 
-	// Check self-collision
+	// Check self-collision; a ghost passes through its own body.
 	selfHit := (!growing && newHead != tail.Pos && g.Snake.Occupies(newHead)) ||
 		(growing && g.Snake.Occupies(newHead))
+	if g.PowerUps.Active(entities.PowerGhost) {
+		selfHit = false
+	}
 
-	return outOfBounds || selfHit
+	// Solid hazards block the snake; ones still warning can be crossed.
+	hazardHit := false
+	if hz := g.hazardAt(newHead); hz != nil {
+		hazardHit = hz.Solid()
+	}
+
+	switch {
+	case outOfBounds:
+		return causeWall
+	case selfHit:
+		return causeSelf
+	case hazardHit:
+		return causeObstacle
+	}
+	return causeNone
+}
+
+// outOfBounds reports whether pos lies outside the playable area, including any
+// rings walled off by a shrinking board.
+func (g *Game) outOfBounds(pos image.Point) bool {
+	inset := g.Hazards.Inset
+	return pos.X < inset || pos.X >= g.gridWidth-inset || pos.Y < inset || pos.Y >= g.gridHeight-inset
 }
 
+// wrap moves an out-of-bounds position to the opposite edge of the playable area.
+func (g *Game) wrap(pos image.Point) image.Point {
+	inset := g.Hazards.Inset
+	w, h := g.playWidth(), g.playHeight()
+	return image.Pt(inset+((pos.X-inset)%w+w)%w, inset+((pos.Y-inset)%h+h)%h)
+}
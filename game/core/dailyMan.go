@@ -0,0 +1,236 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"log"
+	"math/rand"
+	"os"
+	"snakeGame/game/entities"
+	"snakeGame/game/render"
+	"snakeGame/game/ui"
+	"time"
+)
+
+// dailyResultVersion is bumped whenever a rules change would make old result
+// files simulate differently.
+const dailyResultVersion = 3
+
+// dailyDateLayout is how challenge dates are written, always in UTC so that
+// everyone shares the same day regardless of time zone.
+const dailyDateLayout = "2006-01-02"
+
+// dailyDifficulty is the difficulty every daily challenge is played on, whatever
+// the player has chosen in settings.
+const dailyDifficulty = "Normal"
+
+// DailyChallenge is the run everyone plays on a given day: the seed, the rocks on
+// the board and the rule modifiers all follow from the date.
+type DailyChallenge struct {
+	Date       string
+	Seed       int64
+	Modifiers  []string // names of the modifiers in effect
+	Mode       *Mode
+	Difficulty *Difficulty
+}
+
+// dailyModifier tweaks the daily challenge's rules.
+type dailyModifier struct {
+	Name  string
+	Apply func(m *Mode)
+}
+
+// dailyModifiers are the rule changes a daily challenge draws from.
+var dailyModifiers = []dailyModifier{
+	{Name: "Rockfall", Apply: func(m *Mode) { m.Rocks += 8 }},
+	{Name: "Hazards", Apply: func(m *Mode) { m.HazardEvery, m.HazardWarning = 40, 12 }},
+	{Name: "Closing In", Apply: func(m *Mode) { m.ShrinkEvery, m.HazardWarning = 250, 12 }},
+	{Name: "Golden Hour", Apply: func(m *Mode) { m.FoodWeights[entities.FoodGoldenApple] = 30 }},
+	{Name: "Poison Patch", Apply: func(m *Mode) { m.FoodWeights[entities.FoodPoison] = 25 }},
+	{Name: "Berry Bush", Apply: func(m *Mode) { m.FoodWeights[entities.FoodBerry] = 30 }},
+	{Name: "Combo Frenzy", Apply: func(m *Mode) { m.Scoring.ComboWindow, m.Scoring.MaxCombo = 25, 10 }},
+}
+
+// dailyModifierCount is how many modifiers each challenge uses.
+const dailyModifierCount = 2
+
+// NewDailyChallenge builds the challenge for the UTC day containing t.
+func NewDailyChallenge(t time.Time) *DailyChallenge {
+	date := t.UTC().Format(dailyDateLayout)
+	h := fnv.New64a()
+	h.Write([]byte("snake-daily:" + date))
+	seed := int64(h.Sum64())
+
+	// The challenge's own rng only decides the rules; the run itself is seeded
+	// separately with the same seed.
+	rng := rand.New(rand.NewSource(seed))
+	classic := Modes[0]
+	mode := &Mode{
+		Name:        "Daily Challenge",
+		Scoring:     classic.Scoring,
+		ScoreTable:  "daily",
+		Rocks:       4 + rng.Intn(6),
+		FoodWeights: make(map[entities.FoodKind]int),
+	}
+	// Built-in presets only, so a local difficulties file cannot change the rules.
+	d := &DailyChallenge{
+		Date:       date,
+		Seed:       seed,
+		Mode:       mode,
+		Difficulty: difficultyByName(builtinDifficulties, dailyDifficulty),
+	}
+	for _, i := range rng.Perm(len(dailyModifiers))[:dailyModifierCount] {
+		dailyModifiers[i].Apply(mode)
+		d.Modifiers = append(d.Modifiers, dailyModifiers[i].Name)
+	}
+	return d
+}
+
+// DailyResult is the exported record of an official daily attempt. Anyone can
+// re-simulate it from the seed and inputs to confirm the score.
+type DailyResult struct {
+	Version    int          `json:"version"`
+	Date       string       `json:"date"`
+	Seed       int64        `json:"seed"`
+	Modifiers  []string     `json:"modifiers"`
+	Difficulty string       `json:"difficulty"`
+	GridWidth  int          `json:"grid_width"`
+	GridHeight int          `json:"grid_height"`
+	Score      int          `json:"score"`
+	Ticks      int          `json:"ticks"`
+	Inputs     []InputEvent `json:"inputs"`
+}
+
+// dailyResultPath is where the result for a date is exported.
+func dailyResultPath(date string) string {
+	return fmt.Sprintf("daily_%s.json", date)
+}
+
+// startDaily sets up today's challenge as the current mode and starts a run with
+// its difficulty.
+func (g *Game) startDaily() {
+	g.Daily = NewDailyChallenge(time.Now())
+	g.Mode = g.Daily.Mode
+	g.startRun()
+}
+
+// leaveDaily returns to the mode chosen in settings; the next run takes the
+// difficulty chosen there too.
+func (g *Game) leaveDaily() {
+	g.Daily = nil
+	g.Mode = modeByName(g.Settings.Mode)
+}
+
+// recordDaily saves the official attempt's score and exports its result file.
+func (g *Game) recordDaily() {
+	g.Profile.RecordDaily(g.Daily.Date, g.State.Score)
+	result := DailyResult{
+		Version:    dailyResultVersion,
+		Date:       g.Daily.Date,
+		Seed:       g.Daily.Seed,
+		Modifiers:  g.Daily.Modifiers,
+		Difficulty: g.Daily.Difficulty.Name,
+		GridWidth:  g.gridWidth,
+		GridHeight: g.gridHeight,
+		Score:      g.State.Score,
+		Ticks:      g.State.Ticks,
+		Inputs:     g.Inputs.Events,
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode daily result: %v", err)
+		return
+	}
+	path := dailyResultPath(g.Daily.Date)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Printf("Failed to save daily result: %v", err)
+		return
+	}
+	log.Printf("Daily result exported to %s", path)
+}
+
+// dailyStatus describes today's challenge for the title screen.
+func (g *Game) dailyStatus() string {
+	today := NewDailyChallenge(time.Now())
+	if score, played := g.Profile.DailyScore(today.Date); played {
+		return fmt.Sprintf("Daily done today: %d", score)
+	}
+	return fmt.Sprintf("Daily: %s + %s", today.Modifiers[0], today.Modifiers[1])
+}
+
+// VerifyDailyResult re-simulates a daily result file and checks that its seed and
+// difficulty match its date and that replaying its inputs produces the claimed score.
+func VerifyDailyResult(path string) (*DailyResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var result DailyResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	if result.Version != dailyResultVersion {
+		return &result, fmt.Errorf("result version %d, this build simulates version %d", result.Version, dailyResultVersion)
+	}
+	day, err := time.Parse(dailyDateLayout, result.Date)
+	if err != nil {
+		return &result, fmt.Errorf("invalid date %q: %w", result.Date, err)
+	}
+	daily := NewDailyChallenge(day)
+	if daily.Seed != result.Seed {
+		return &result, fmt.Errorf("seed %d does not belong to %s", result.Seed, result.Date)
+	}
+	if result.Difficulty != daily.Difficulty.Name {
+		return &result, fmt.Errorf("difficulty %q, %s is played on %q", result.Difficulty, result.Date, daily.Difficulty.Name)
+	}
+	if result.GridWidth <= 0 || result.GridHeight <= 0 {
+		return &result, fmt.Errorf("invalid grid %dx%d", result.GridWidth, result.GridHeight)
+	}
+
+	g := newHeadlessGame(result.GridWidth, result.GridHeight, daily.Mode, daily)
+	g.resetGame()
+	playback := NewInputPlayback(result.Inputs)
+	for i := 0; i <= result.Ticks && !g.State.GameOver; i++ {
+		playback.Apply(g.State.Ticks, g.Snake)
+		g.tick()
+	}
+	switch {
+	case !g.State.GameOver:
+		return &result, fmt.Errorf("run still alive after %d moves", result.Ticks)
+	case g.State.Ticks != result.Ticks:
+		return &result, fmt.Errorf("run ended after %d moves, file claims %d", g.State.Ticks, result.Ticks)
+	case g.State.Score != result.Score:
+		return &result, fmt.Errorf("replay scored %d, file claims %d", g.State.Score, result.Score)
+	}
+	return &result, nil
+}
+
+// newHeadlessGame builds a game with just enough to simulate moves: no window,
+// sound, menus or effects. daily may be nil for runs outside the daily challenge.
+func newHeadlessGame(gridWidth, gridHeight int, mode *Mode, daily *DailyChallenge) *Game {
+	g := &Game{
+		State:       NewStateManager(),
+		Speed:       NewSpeedManager(),
+		gridWidth:   gridWidth,
+		gridHeight:  gridHeight,
+		defaultGrid: image.Pt(gridWidth, gridHeight),
+		cellSize:    1,
+		Effects:     render.NewParticleManager(nil),
+		Theme:       &ui.Theme{},
+		PowerUps:    NewPowerUpManager(),
+		Hazards:     NewHazardManager(),
+		Inputs:      &InputLog{},
+		Daily:       daily,
+		Mode:        mode,
+		Scoring:     NewScoreManager(mode.Scoring),
+	}
+	g.Effects.Enabled = false
+	if daily != nil {
+		g.setDifficulty(daily.Difficulty)
+	} else {
+		g.setDifficulty(difficultyByName(builtinDifficulties, "Normal"))
+	}
+	return g
+}
@@ -0,0 +1,210 @@
+package core
+
+import (
+	"encoding/json"
+	"image"
+	"os"
+	"path/filepath"
+	"slices"
+	"snakeGame/game/entities"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewDailyChallengeDate(t *testing.T) {
+	est := time.FixedZone("EST", -5*60*60)
+	tests := []struct {
+		name string
+		t    time.Time
+		date string
+	}{
+		{"midnight UTC", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), "2026-03-02"},
+		{"last second of the day", time.Date(2026, 3, 2, 23, 59, 59, 0, time.UTC), "2026-03-02"},
+		{"evening behind UTC", time.Date(2026, 3, 1, 23, 30, 0, 0, est), "2026-03-02"},
+		{"morning behind UTC", time.Date(2026, 3, 1, 8, 0, 0, 0, est), "2026-03-01"},
+		{"leap day", time.Date(2028, 2, 29, 12, 0, 0, 0, time.UTC), "2028-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDailyChallenge(tt.t)
+			if d.Date != tt.date {
+				t.Fatalf("date = %s, want %s", d.Date, tt.date)
+			}
+			day, _ := time.Parse(dailyDateLayout, tt.date)
+			same := NewDailyChallenge(day)
+			if d.Seed != same.Seed || !slices.Equal(d.Modifiers, same.Modifiers) || d.Mode.Rocks != same.Mode.Rocks {
+				t.Errorf("challenge differs from the one at the start of %s", tt.date)
+			}
+		})
+	}
+}
+
+func TestNewDailyChallengeRules(t *testing.T) {
+	var names []string
+	for _, m := range dailyModifiers {
+		names = append(names, m.Name)
+	}
+	seeds := make(map[int64]string)
+	for day := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2026; day = day.AddDate(0, 0, 1) {
+		d := NewDailyChallenge(day)
+		if other, ok := seeds[d.Seed]; ok {
+			t.Fatalf("%s has the same seed as %s", d.Date, other)
+		}
+		seeds[d.Seed] = d.Date
+
+		if len(d.Modifiers) != dailyModifierCount {
+			t.Fatalf("%s: %d modifiers, want %d", d.Date, len(d.Modifiers), dailyModifierCount)
+		}
+		if d.Modifiers[0] == d.Modifiers[1] {
+			t.Errorf("%s: modifier %s drawn twice", d.Date, d.Modifiers[0])
+		}
+		for _, name := range d.Modifiers {
+			if !slices.Contains(names, name) {
+				t.Errorf("%s: unknown modifier %q", d.Date, name)
+			}
+		}
+		if d.Difficulty.Name != dailyDifficulty {
+			t.Errorf("%s: difficulty %s, want %s", d.Date, d.Difficulty.Name, dailyDifficulty)
+		}
+		if d.Mode.ScoreTable != "daily" {
+			t.Errorf("%s: score table %q, want daily", d.Date, d.Mode.ScoreTable)
+		}
+	}
+}
+
+func TestDailyBoardFollowsSeed(t *testing.T) {
+	tests := []struct {
+		name string
+		date time.Time
+		w, h int
+	}{
+		{"small board", time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC), 12, 10},
+		{"default board", time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC), 20, 15},
+		{"wide board", time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC), 40, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var boards [2]string
+			for i := range boards {
+				daily := NewDailyChallenge(tt.date)
+				g := newHeadlessGame(tt.w, tt.h, daily.Mode, daily)
+				g.resetGame()
+				if g.Seed != daily.Seed {
+					t.Fatalf("run seed = %d, want the daily seed %d", g.Seed, daily.Seed)
+				}
+				boards[i] = boardLayout(g)
+			}
+			if boards[0] != boards[1] {
+				t.Errorf("two starts of the same daily differ:\n%s\n%s", boards[0], boards[1])
+			}
+		})
+	}
+}
+
+// boardLayout describes where the rocks and food are at the start of a run.
+func boardLayout(g *Game) string {
+	var b strings.Builder
+	for _, hz := range g.Hazards.Hazards {
+		b.WriteString("rock " + hz.Pos.String() + " ")
+	}
+	for _, food := range g.Foods {
+		b.WriteString("food " + food.Pos.String() + " ")
+	}
+	return b.String()
+}
+
+func TestVerifyDailyResult(t *testing.T) {
+	daily := NewDailyChallenge(time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC))
+	recorded := playDaily(t, daily, 20, 15)
+
+	tests := []struct {
+		name    string
+		change  func(r *DailyResult)
+		wantErr string // "" when the result should verify
+	}{
+		{"recorded run", func(r *DailyResult) {}, ""},
+		{"raised score", func(r *DailyResult) { r.Score++ }, "replay scored"},
+		{"seed of another day", func(r *DailyResult) { r.Seed++ }, "does not belong"},
+		{"other difficulty", func(r *DailyResult) { r.Difficulty = "Hard" }, "difficulty"},
+		{"older version", func(r *DailyResult) { r.Version = 1 }, "version"},
+		{"cut short", func(r *DailyResult) { r.Ticks-- }, "still alive"},
+		{"bad date", func(r *DailyResult) { r.Date = "June 15" }, "invalid date"},
+		{"no board", func(r *DailyResult) { r.GridWidth = 0 }, "invalid grid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := recorded
+			r.Inputs = slices.Clone(recorded.Inputs)
+			tt.change(&r)
+			data, err := json.Marshal(r)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(t.TempDir(), dailyResultPath(r.Date))
+			if err := os.WriteFile(path, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = VerifyDailyResult(path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("verified, want an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("error %q, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// playDaily plays a daily challenge headless, steering towards the apple for a
+// while and then straight on until the snake crashes, and returns the result an
+// official attempt would export.
+func playDaily(t *testing.T, daily *DailyChallenge, w, h int) DailyResult {
+	t.Helper()
+	g := newHeadlessGame(w, h, daily.Mode, daily)
+	g.resetGame()
+	for i := 0; i < 5000 && !g.State.GameOver; i++ {
+		if i < 1000 {
+			steerToApple(g)
+		}
+		g.tick()
+	}
+	if !g.State.GameOver {
+		t.Fatalf("snake still alive after %d moves", g.State.Ticks)
+	}
+	return DailyResult{
+		Version:    dailyResultVersion,
+		Date:       daily.Date,
+		Seed:       daily.Seed,
+		Modifiers:  daily.Modifiers,
+		Difficulty: daily.Difficulty.Name,
+		GridWidth:  w,
+		GridHeight: h,
+		Score:      g.State.Score,
+		Ticks:      g.State.Ticks,
+		Inputs:     slices.Clone(g.Inputs.Events),
+	}
+}
+
+// steerToApple points the snake at the first apple, turning aside rather than
+// reversing into itself.
+func steerToApple(g *Game) {
+	for _, food := range g.Foods {
+		if food.Kind != entities.FoodApple {
+			continue
+		}
+		d := food.Pos.Sub(g.Snake.HeadPos())
+		dir := image.Pt(entities.Sign(d.X), 0)
+		if d.X == 0 {
+			dir = image.Pt(0, entities.Sign(d.Y))
+		}
+		if dir == g.Snake.Dir.Mul(-1) {
+			dir = image.Pt(dir.Y, dir.X)
+		}
+		g.Snake.PendingDir = dir
+		return
+	}
+}
@@ -0,0 +1,247 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+)
+
+// difficultiesFile optionally adds or overrides difficulty presets.
+const difficultiesFile = "difficulties.json"
+
+// CurveKind selects how a SpeedCurve turns a level into a speed.
+type CurveKind string
+
+const (
+	CurveLinear      CurveKind = "linear"      // Base + Step*(level-1)
+	CurveExponential CurveKind = "exponential" // Base * Step^(level-1)
+	CurveStepped     CurveKind = "stepped"     // Base + Step for every Every levels
+	CurveTable       CurveKind = "table"       // Table[level-1], last entry repeats
+)
+
+// SpeedCurve maps a level to the snake's speed in moves per second.
+type SpeedCurve struct {
+	Kind  CurveKind `json:"kind"`
+	Base  float64   `json:"base"`
+	Step  float64   `json:"step"`
+	Every int       `json:"every,omitempty"`
+	Table []float64 `json:"table,omitempty"`
+	Max   float64   `json:"max,omitempty"` // 0 means uncapped
+}
+
+// MovesPerSecond returns the speed for the given level (starting at 1).
+func (c SpeedCurve) MovesPerSecond(level int) float64 {
+	n := float64(level - 1)
+	var mps float64
+	switch c.Kind {
+	case CurveExponential:
+		mps = c.Base * math.Pow(c.Step, n)
+	case CurveStepped:
+		every := c.Every
+		if every <= 0 {
+			every = 1
+		}
+		mps = c.Base + c.Step*float64((level-1)/every)
+	case CurveTable:
+		if len(c.Table) == 0 {
+			mps = c.Base
+			break
+		}
+		i := level - 1
+		if i >= len(c.Table) {
+			i = len(c.Table) - 1
+		}
+		if i < 0 {
+			i = 0
+		}
+		mps = c.Table[i]
+	default:
+		mps = c.Base + c.Step*n
+	}
+	if c.Max > 0 && mps > c.Max {
+		mps = c.Max
+	}
+	if mps <= 0 {
+		mps = 1
+	}
+	return mps
+}
+
+// LevelRule maps a score to a level. Thresholds, when set, list the score needed to
+// reach level 2, 3, ...; after the last one, levels continue every PointsPerLevel.
+type LevelRule struct {
+	PointsPerLevel int   `json:"points_per_level"`
+	Thresholds     []int `json:"thresholds,omitempty"`
+}
+
+// LevelFor returns the level reached with the given score.
+func (r LevelRule) LevelFor(score int) int {
+	level := 1
+	for _, t := range r.Thresholds {
+		if score < t {
+			return level
+		}
+		level++
+	}
+	if r.PointsPerLevel <= 0 {
+		return level
+	}
+	last := 0
+	if len(r.Thresholds) > 0 {
+		last = r.Thresholds[len(r.Thresholds)-1]
+	}
+	return level + (score-last)/r.PointsPerLevel
+}
+
+// Progress returns how far the score is towards the next level, from 0 to 1.
+func (r LevelRule) Progress(score int) float64 {
+	start, next := r.levelBounds(r.LevelFor(score))
+	if next <= start {
+		return 0
+	}
+	return float64(score-start) / float64(next-start)
+}
+
+// levelBounds returns the score that starts the level and the score that ends it.
+func (r LevelRule) levelBounds(level int) (int, int) {
+	i := level - 1
+	if i < len(r.Thresholds) {
+		start := 0
+		if i > 0 {
+			start = r.Thresholds[i-1]
+		}
+		return start, r.Thresholds[i]
+	}
+	last := 0
+	if len(r.Thresholds) > 0 {
+		last = r.Thresholds[len(r.Thresholds)-1]
+	}
+	start := last + (i-len(r.Thresholds))*r.PointsPerLevel
+	return start, start + r.PointsPerLevel
+}
+
+// Difficulty is a named preset combining a level rule and a speed curve.
+type Difficulty struct {
+	Name   string     `json:"name"`
+	Levels LevelRule  `json:"levels"`
+	Speed  SpeedCurve `json:"speed"`
+}
+
+// builtinDifficulties are always available; Normal matches the original
+// "20 - 2*level frames, at least 5" rule at 60 TPS.
+var builtinDifficulties = []*Difficulty{
+	{
+		Name:   "Easy",
+		Levels: LevelRule{PointsPerLevel: 8},
+		Speed:  SpeedCurve{Kind: CurveLinear, Base: 2.5, Step: 0.25, Max: 6},
+	},
+	{
+		Name:   "Normal",
+		Levels: LevelRule{PointsPerLevel: 5},
+		Speed:  SpeedCurve{Kind: CurveTable, Table: []float64{3.33, 3.75, 4.29, 5, 6, 7.5, 10, 12}},
+	},
+	{
+		Name:   "Hard",
+		Levels: LevelRule{PointsPerLevel: 5},
+		Speed:  SpeedCurve{Kind: CurveExponential, Base: 4, Step: 1.15, Max: 15},
+	},
+	{
+		Name:   "Insane",
+		Levels: LevelRule{PointsPerLevel: 8, Thresholds: []int{3, 6, 10, 15, 21, 28}},
+		Speed:  SpeedCurve{Kind: CurveStepped, Base: 6, Step: 2, Every: 2, Max: 20},
+	},
+}
+
+// loadDifficulties returns the built-in presets plus any defined in path. A preset
+// in the file with the same name as a built-in one replaces it.
+func loadDifficulties(path string) []*Difficulty {
+	presets := append([]*Difficulty(nil), builtinDifficulties...)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return presets
+	}
+	var custom []*Difficulty
+	if err := json.Unmarshal(data, &custom); err != nil {
+		log.Printf("Failed to parse difficulties: %v", err)
+		return presets
+	}
+	for i, d := range custom {
+		if d == nil {
+			continue
+		}
+		if err := d.validate(); err != nil {
+			log.Printf("Skipping difficulty %d (%q): %v", i+1, d.Name, err)
+			continue
+		}
+		replaced := false
+		for i, p := range presets {
+			if p.Name == d.Name {
+				presets[i] = d
+				replaced = true
+			}
+		}
+		if !replaced {
+			presets = append(presets, d)
+		}
+	}
+	return presets
+}
+
+// validate reports a preset that would stop the snake or break the level lookups.
+func (d *Difficulty) validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("no name")
+	}
+	if d.Levels.PointsPerLevel < 0 {
+		return fmt.Errorf("points per level %d is negative", d.Levels.PointsPerLevel)
+	}
+	if !increasing(d.Levels.Thresholds, 0) {
+		return fmt.Errorf("level thresholds %v are not positive and rising", d.Levels.Thresholds)
+	}
+	c := d.Speed
+	switch c.Kind {
+	case CurveTable:
+		if len(c.Table) == 0 {
+			return fmt.Errorf("empty speed table")
+		}
+		if !increasing(c.Table, 0) {
+			return fmt.Errorf("speed table %v is not positive and rising", c.Table)
+		}
+	case "", CurveLinear, CurveExponential, CurveStepped: // no kind is linear
+		if c.Base <= 0 {
+			return fmt.Errorf("base speed %v is not above 0", c.Base)
+		}
+		if c.Kind == CurveExponential && c.Step <= 0 {
+			return fmt.Errorf("speed factor %v is not above 0", c.Step)
+		}
+	default:
+		return fmt.Errorf("unknown speed curve %q", c.Kind)
+	}
+	if c.Max < 0 || c.Every < 0 {
+		return fmt.Errorf("negative max speed or step interval")
+	}
+	return nil
+}
+
+// increasing reports whether every value is above the one before it, starting above floor.
+func increasing[T int | float64](values []T, floor T) bool {
+	for _, v := range values {
+		if v <= floor {
+			return false
+		}
+		floor = v
+	}
+	return true
+}
+
+// difficultyByName returns the preset with the given name, falling back to Normal.
+func difficultyByName(presets []*Difficulty, name string) *Difficulty {
+	for _, d := range presets {
+		if d.Name == name {
+			return d
+		}
+	}
+	return difficultyByName(builtinDifficulties, "Normal")
+}
@@ -0,0 +1,158 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestLevelRule(t *testing.T) {
+	every5 := LevelRule{PointsPerLevel: 5}
+	stepped := LevelRule{PointsPerLevel: 8, Thresholds: []int{3, 6, 10}}
+	capped := LevelRule{Thresholds: []int{2, 4}}
+	tests := []struct {
+		name     string
+		rule     LevelRule
+		score    int
+		level    int
+		progress float64
+	}{
+		{"start", every5, 0, 1, 0},
+		{"partway", every5, 2, 1, 0.4},
+		{"just below", every5, 4, 1, 0.8},
+		{"on a level", every5, 5, 2, 0},
+		{"several levels", every5, 27, 6, 0.4},
+		{"before the first threshold", stepped, 2, 1, 2.0 / 3},
+		{"on the first threshold", stepped, 3, 2, 0},
+		{"between thresholds", stepped, 8, 3, 0.5},
+		{"on the last threshold", stepped, 10, 4, 0},
+		{"past the thresholds", stepped, 14, 4, 0.5},
+		{"a level past the thresholds", stepped, 18, 5, 0},
+		{"thresholds only", capped, 3, 2, 0.5},
+		{"past thresholds only", capped, 100, 3, 0},
+		{"no rule", LevelRule{}, 50, 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.LevelFor(tt.score); got != tt.level {
+				t.Errorf("LevelFor(%d) = %d, want %d", tt.score, got, tt.level)
+			}
+			if got := tt.rule.Progress(tt.score); math.Abs(got-tt.progress) > 1e-9 {
+				t.Errorf("Progress(%d) = %v, want %v", tt.score, got, tt.progress)
+			}
+		})
+	}
+}
+
+func TestSpeedCurve(t *testing.T) {
+	tests := []struct {
+		name  string
+		curve SpeedCurve
+		level int
+		mps   float64
+	}{
+		{"linear start", SpeedCurve{Kind: CurveLinear, Base: 2.5, Step: 0.25, Max: 6}, 1, 2.5},
+		{"linear climb", SpeedCurve{Kind: CurveLinear, Base: 2.5, Step: 0.25, Max: 6}, 5, 3.5},
+		{"linear cap", SpeedCurve{Kind: CurveLinear, Base: 2.5, Step: 0.25, Max: 6}, 50, 6},
+		{"unknown kind is linear", SpeedCurve{Kind: "wavy", Base: 3, Step: 1}, 3, 5},
+		{"exponential start", SpeedCurve{Kind: CurveExponential, Base: 4, Step: 1.15, Max: 15}, 1, 4},
+		{"exponential climb", SpeedCurve{Kind: CurveExponential, Base: 4, Step: 1.15, Max: 15}, 3, 4 * 1.15 * 1.15},
+		{"exponential cap", SpeedCurve{Kind: CurveExponential, Base: 4, Step: 1.15, Max: 15}, 20, 15},
+		{"stepped first step", SpeedCurve{Kind: CurveStepped, Base: 6, Step: 2, Every: 2}, 2, 6},
+		{"stepped second step", SpeedCurve{Kind: CurveStepped, Base: 6, Step: 2, Every: 2}, 3, 8},
+		{"stepped without every", SpeedCurve{Kind: CurveStepped, Base: 6, Step: 2}, 3, 10},
+		{"table start", SpeedCurve{Kind: CurveTable, Table: []float64{3, 4, 5}}, 1, 3},
+		{"table end", SpeedCurve{Kind: CurveTable, Table: []float64{3, 4, 5}}, 3, 5},
+		{"table repeats last", SpeedCurve{Kind: CurveTable, Table: []float64{3, 4, 5}}, 9, 5},
+		{"table below level 1", SpeedCurve{Kind: CurveTable, Table: []float64{3, 4, 5}}, 0, 3},
+		{"empty table uses base", SpeedCurve{Kind: CurveTable, Base: 2}, 4, 2},
+		{"never stops", SpeedCurve{Kind: CurveLinear, Base: 1, Step: -1}, 5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.curve.MovesPerSecond(tt.level); math.Abs(got-tt.mps) > 1e-9 {
+				t.Errorf("MovesPerSecond(%d) = %v, want %v", tt.level, got, tt.mps)
+			}
+		})
+	}
+}
+
+func TestDifficultyInGame(t *testing.T) {
+	tests := []struct {
+		difficulty string
+		score      int
+	}{
+		{"Easy", 0},
+		{"Easy", 17},
+		{"Normal", 12},
+		{"Hard", 40},
+		{"Insane", 4},
+		{"Insane", 60},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s at %d", tt.difficulty, tt.score), func(t *testing.T) {
+			d := difficultyByName(builtinDifficulties, tt.difficulty)
+			g := newHeadlessGame(20, 15, Modes[0], nil)
+			g.setDifficulty(d)
+			g.resetGame()
+			g.State.AddScore(tt.score)
+			g.Speed.AdjustSpeedByLevel(g.State.Level)
+
+			level := d.Levels.LevelFor(tt.score)
+			if g.State.Level != level {
+				t.Errorf("level = %d, want %d", g.State.Level, level)
+			}
+			// The speed manager keeps the time per move, so allow for rounding.
+			if got, want := g.Speed.MovesPerSecond(), d.Speed.MovesPerSecond(level); math.Abs(got-want) > want*1e-6 {
+				t.Errorf("speed = %v moves/s, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLoadDifficulties(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		added   string // preset the file adds or replaces; "" when it is skipped
+		presets int
+	}{
+		{"new preset", `[{"name": "Crawl", "levels": {"points_per_level": 10}, "speed": {"kind": "linear", "base": 1, "step": 0.1}}]`, "Crawl", 5},
+		{"replaced preset", `[{"name": "Hard", "levels": {"thresholds": [2, 5]}, "speed": {"kind": "table", "table": [4, 6, 9]}}]`, "Hard", 4},
+		{"null entry", `[null]`, "", 4},
+		{"no name", `[{"levels": {"points_per_level": 5}, "speed": {"kind": "linear", "base": 3}}]`, "", 4},
+		{"zero base", `[{"name": "Stop", "speed": {"kind": "linear", "base": 0, "step": 1}}]`, "", 4},
+		{"negative base", `[{"name": "Stop", "speed": {"kind": "stepped", "base": -2, "step": 1}}]`, "", 4},
+		{"zero factor", `[{"name": "Stop", "speed": {"kind": "exponential", "base": 4, "step": 0}}]`, "", 4},
+		{"empty table", `[{"name": "Stop", "speed": {"kind": "table"}}]`, "", 4},
+		{"falling table", `[{"name": "Stop", "speed": {"kind": "table", "table": [5, 4]}}]`, "", 4},
+		{"zero in table", `[{"name": "Stop", "speed": {"kind": "table", "table": [0, 4]}}]`, "", 4},
+		{"repeated threshold", `[{"name": "Flat", "levels": {"thresholds": [3, 3]}, "speed": {"kind": "linear", "base": 3}}]`, "", 4},
+		{"negative points per level", `[{"name": "Flat", "levels": {"points_per_level": -5}, "speed": {"kind": "linear", "base": 3}}]`, "", 4},
+		{"unknown curve", `[{"name": "Wavy", "speed": {"kind": "wavy", "base": 3}}]`, "", 4},
+		{"not a list", `{"name": "Crawl"}`, "", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), difficultiesFile)
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			presets := loadDifficulties(path)
+			if len(presets) != tt.presets {
+				t.Fatalf("%d presets, want %d", len(presets), tt.presets)
+			}
+			for _, d := range presets {
+				if err := d.validate(); err != nil {
+					t.Errorf("%s: %v", d.Name, err)
+				}
+				if d.Name == tt.added && slices.Contains(builtinDifficulties, d) {
+					t.Errorf("%s is still the built-in preset", d.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,673 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"snakeGame/game/entities"
+	"snakeGame/game/render"
+	"strings"
+	"unicode"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// EditorTool is what painting a cell does in the level editor.
+type EditorTool int
+
+const (
+	ToolWall EditorTool = iota
+	ToolPortal
+	ToolSpawn
+	ToolFoodZone
+	ToolPatrol
+	ToolBouncer
+	ToolErase
+)
+
+// editorTools names the tools in the order Tab cycles through them; the number
+// keys pick them directly.
+var editorTools = []string{"Wall", "Portal", "Spawn", "Food Zone", "Patrol", "Bouncer", "Erase"}
+
+// editorToolKeys select each tool.
+var editorToolKeys = []ebiten.Key{ebiten.Key1, ebiten.Key2, ebiten.Key3, ebiten.Key4, ebiten.Key5, ebiten.Key6, ebiten.Key7}
+
+// editorCursorKeys move the editor's cursor.
+var editorCursorKeys = []struct {
+	key ebiten.Key
+	dir image.Point
+}{
+	{ebiten.KeyArrowUp, entities.Up},
+	{ebiten.KeyArrowDown, entities.Down},
+	{ebiten.KeyArrowLeft, entities.Left},
+	{ebiten.KeyArrowRight, entities.Right},
+}
+
+// dragPaints reports whether holding the button paints every cell passed over;
+// portals, the spawn and enemies are placed one click at a time.
+func (t EditorTool) dragPaints() bool {
+	return t == ToolWall || t == ToolFoodZone || t == ToolErase
+}
+
+// Editor limits and timing.
+const (
+	editorStatusFrames = 180 // how long a message stays in the HUD
+	maxMetaLength      = 24  // longest map name or author that can be typed
+)
+
+// Editor is the level editor's state: the map being built and how it is being edited.
+type Editor struct {
+	Map         *Map        // working copy; test play and saving use it as it stands
+	Tool        EditorTool  // what painting a cell does
+	Cursor      image.Point // cell the keyboard paints; follows the mouse
+	portalStart *cell       // first end of a portal waiting for its partner
+	patrolStart *cell       // first waypoint of the patrol whose path is being laid
+	typing      *string     // metadata field receiving typed text; nil when not typing
+	open        int         // installed map offered by the Open item; 0 = a new map
+	dragging    bool        // the left button went down inside the editor
+	lastMouse   image.Point
+
+	status       string // last message, e.g. a save result
+	statusFrames int
+
+	// The snake and enemies as they start on the map, rebuilt when it changes.
+	snake  *entities.SnakeController
+	movers []*entities.Mover
+}
+
+// NewEditor starts the editor on an empty map of the given size.
+func NewEditor(w, h int) *Editor {
+	e := &Editor{Map: &Map{Width: w, Height: h}}
+	e.refresh()
+	return e
+}
+
+// refresh rebuilds the snake and enemies drawn at their starts after the map changes.
+func (e *Editor) refresh() {
+	m := e.Map
+	start, dir := m.start(m.Width, m.Height)
+	e.snake = entities.NewSnakeControllerFacing(start, dir, m.Width, m.Height)
+	e.movers = e.movers[:0]
+	for _, d := range m.Movers {
+		e.movers = append(e.movers, d.newMover())
+	}
+}
+
+// setStatus shows a message in the editor's HUD for a while.
+func (e *Editor) setStatus(format string, args ...any) {
+	e.status = fmt.Sprintf(format, args...)
+	e.statusFrames = editorStatusFrames
+}
+
+// setTool switches tools, dropping a half-placed portal and ending the patrol path
+// being laid where it is.
+func (e *Editor) setTool(t EditorTool) {
+	e.Tool = t
+	e.portalStart = nil
+	e.patrolStart = nil
+}
+
+// paint applies the current tool to c.
+func (e *Editor) paint(c cell) {
+	m := e.Map
+	switch e.Tool {
+	case ToolWall:
+		if slices.Contains(m.Walls, c) {
+			return
+		}
+		m.clearCell(c)
+		m.Walls = append(m.Walls, c)
+	case ToolFoodZone:
+		if slices.Contains(m.FoodZones, c) {
+			return
+		}
+		m.clearCell(c)
+		m.FoodZones = append(m.FoodZones, c)
+	case ToolPortal:
+		e.placePortal(c)
+	case ToolSpawn:
+		e.placeSpawn(c)
+	case ToolPatrol:
+		e.placePatrol(c)
+	case ToolBouncer:
+		e.placeBouncer(c)
+	case ToolErase:
+		e.erase(c)
+		return
+	}
+	e.refresh()
+}
+
+// placePortal places one end of a portal: the first click marks it and the second
+// links it to the first. Clicking the marked cell again cancels it.
+func (e *Editor) placePortal(c cell) {
+	switch {
+	case e.portalStart == nil:
+		e.Map.clearCell(c)
+		e.portalStart = &c
+	case *e.portalStart == c:
+		e.portalStart = nil
+	default:
+		e.Map.clearCell(c)
+		e.Map.Portals = append(e.Map.Portals, portalDef{A: *e.portalStart, B: c})
+		e.portalStart = nil
+	}
+}
+
+// placeSpawn puts the snake's start at c, or turns it clockwise when it is already
+// there. Directions that would leave part of the snake off the board are skipped.
+func (e *Editor) placeSpawn(c cell) {
+	dir := entities.Right
+	if s := e.Map.Spawn; s != nil {
+		dir = s.dir()
+		if s.Pos == c {
+			dir = clockwise(dir)
+		}
+	}
+	for range 4 {
+		if spawnFits(c.point(), dir, e.Map.Width, e.Map.Height) {
+			e.Map.Spawn = &spawnDef{Pos: c, Dir: toCell(dir)}
+			return
+		}
+		dir = clockwise(dir)
+	}
+	e.setStatus("The snake does not fit there")
+}
+
+// placePatrol lays a patrol's path: the first click starts a patrol there and
+// each further click adds a waypoint. Clicking the last waypoint again ends the
+// path; clicking the first one ends it with the patrol looping back to the start.
+func (e *Editor) placePatrol(c cell) {
+	d := e.Map.patrolAt(e.patrolStart)
+	if d == nil {
+		e.Map.clearCell(c)
+		e.Map.Movers = append(e.Map.Movers, moverDef{Kind: "patrol", Path: []cell{c}})
+		e.patrolStart = &c
+		e.setStatus("Click waypoints, the last again to end")
+		return
+	}
+	switch {
+	case c == d.Path[len(d.Path)-1]:
+		e.patrolStart = nil
+	case slices.Contains(e.Map.Walls, c) || e.Map.portalEnd(c):
+		e.setStatus("Enemies cannot walk there")
+	case c == d.Path[0]:
+		d.Loop = true
+		e.patrolStart = nil
+		e.setStatus("Patrol loops")
+	default:
+		d.Path = append(d.Path, c)
+	}
+}
+
+// patrolAt returns the patrol starting at start, or nil if there is none.
+func (m *Map) patrolAt(start *cell) *moverDef {
+	if start == nil {
+		return nil
+	}
+	for i, d := range m.Movers {
+		if d.Kind == "patrol" && d.startCell() == *start {
+			return &m.Movers[i]
+		}
+	}
+	return nil
+}
+
+// portalEnd reports whether c is an end of any portal.
+func (m *Map) portalEnd(c cell) bool {
+	return slices.ContainsFunc(m.Portals, func(p portalDef) bool { return p.A == c || p.B == c })
+}
+
+// bouncerDirs are the directions a bouncer can travel in, clockwise from right.
+var bouncerDirs = []cell{{1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}, {0, -1}, {1, -1}}
+
+// placeBouncer puts a bouncer at c heading diagonally, or turns the one already
+// there an eighth turn clockwise.
+func (e *Editor) placeBouncer(c cell) {
+	for i, d := range e.Map.Movers {
+		if d.Kind == "bouncer" && d.Pos == c {
+			next := (slices.Index(bouncerDirs, d.Dir) + 1) % len(bouncerDirs)
+			e.Map.Movers[i].Dir = bouncerDirs[next]
+			return
+		}
+	}
+	e.Map.clearCell(c)
+	e.Map.Movers = append(e.Map.Movers, moverDef{Kind: "bouncer", Pos: c, Dir: cell{1, 1}})
+}
+
+// clockwise turns a direction a quarter turn clockwise on screen.
+func clockwise(d image.Point) image.Point {
+	return image.Pt(-d.Y, d.X)
+}
+
+// erase removes everything at c, including the spawn if the snake's head is there.
+func (e *Editor) erase(c cell) {
+	changed := e.Map.clearCell(c)
+	if s := e.Map.Spawn; s != nil && s.Pos == c {
+		e.Map.Spawn = nil
+		changed = true
+	}
+	if e.portalStart != nil && *e.portalStart == c {
+		e.portalStart = nil
+	}
+	if e.patrolStart != nil && *e.patrolStart == c {
+		e.patrolStart = nil
+	}
+	if changed {
+		e.refresh()
+	}
+}
+
+// clearCell removes the wall, food zone, portal and enemy at c, leaving the cell
+// free for something else. A portal is removed along with its partner, and an
+// enemy if it starts at c. It reports whether anything was removed.
+func (m *Map) clearCell(c cell) bool {
+	before := len(m.Walls) + len(m.FoodZones) + len(m.Portals) + len(m.Movers)
+	m.Walls = slices.DeleteFunc(m.Walls, func(w cell) bool { return w == c })
+	m.FoodZones = slices.DeleteFunc(m.FoodZones, func(z cell) bool { return z == c })
+	m.Portals = slices.DeleteFunc(m.Portals, func(p portalDef) bool { return p.A == c || p.B == c })
+	m.Movers = slices.DeleteFunc(m.Movers, func(d moverDef) bool { return d.startCell() == c })
+	return len(m.Walls)+len(m.FoodZones)+len(m.Portals)+len(m.Movers) != before
+}
+
+// resize changes the grid size by the given steps within the allowed sizes and
+// drops anything left off the board.
+func (e *Editor) resize(dw, dh int) {
+	m := e.Map
+	m.Width = min(max(m.Width+dw, minMapSize), maxMapSize)
+	m.Height = min(max(m.Height+dh, minMapSize), maxMapSize)
+	off := func(c cell) bool { return c[0] < 0 || c[1] < 0 || c[0] >= m.Width || c[1] >= m.Height }
+
+	m.Walls = slices.DeleteFunc(m.Walls, off)
+	m.FoodZones = slices.DeleteFunc(m.FoodZones, off)
+	m.Portals = slices.DeleteFunc(m.Portals, func(p portalDef) bool { return off(p.A) || off(p.B) })
+	m.Movers = slices.DeleteFunc(m.Movers, func(d moverDef) bool {
+		return off(d.startCell()) || slices.ContainsFunc(d.Path, off)
+	})
+	if s := m.Spawn; s != nil && !spawnFits(s.Pos.point(), s.dir(), m.Width, m.Height) {
+		m.Spawn = nil
+	}
+	if e.portalStart != nil && off(*e.portalStart) {
+		e.portalStart = nil
+	}
+	if e.patrolStart != nil && off(*e.patrolStart) {
+		e.patrolStart = nil
+	}
+	e.Cursor = image.Pt(min(e.Cursor.X, m.Width-1), min(e.Cursor.Y, m.Height-1))
+	e.refresh()
+}
+
+// updateTyping edits the metadata field being typed into; Enter or Escape finishes.
+func (e *Editor) updateTyping() {
+	field := e.typing
+	for _, r := range ebiten.AppendInputChars(nil) {
+		if unicode.IsPrint(r) && len([]rune(*field)) < maxMetaLength {
+			*field += string(r)
+		}
+	}
+	if keyRepeated(ebiten.KeyBackspace) && *field != "" {
+		runes := []rune(*field)
+		*field = string(runes[:len(runes)-1])
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeyEscape) {
+		*field = strings.TrimSpace(*field)
+		e.typing = nil
+	}
+}
+
+// fieldText shows a metadata field in the menu, with a cursor while typing.
+func (e *Editor) fieldText(field *string) string {
+	if e.typing == field {
+		return *field + "_"
+	}
+	if *field == "" {
+		return "-"
+	}
+	return *field
+}
+
+// keyRepeated reports whether key was just pressed or has been held long enough to repeat.
+func keyRepeated(key ebiten.Key) bool {
+	d := inpututil.KeyPressDuration(key)
+	return d == 1 || (d > 15 && d%4 == 0)
+}
+
+// openEditor shows the level editor, keeping whatever was being edited last time.
+func (g *Game) openEditor() {
+	if g.Editor == nil {
+		g.Editor = NewEditor(g.defaultGrid.X, g.defaultGrid.Y)
+	}
+	g.Screens.Switch(&editorScreen{g: g}, TransitionFade)
+}
+
+// updateEditor handles painting with the mouse and keyboard for one frame.
+func (g *Game) updateEditor() {
+	e := g.Editor
+	if e.statusFrames > 0 {
+		e.statusFrames--
+		if e.statusFrames == 0 {
+			e.status = ""
+		}
+	}
+	if g.pausePressed() {
+		g.editorMenu.Reset()
+		g.Screens.Push(&editorMenuScreen{g: g}, TransitionNone)
+		return
+	}
+
+	// Tools: Tab cycles, Shift+Tab goes back, number keys pick directly.
+	if inpututil.IsKeyJustPressed(ebiten.KeyTab) {
+		step := 1
+		if ebiten.IsKeyPressed(ebiten.KeyShift) {
+			step = -1
+		}
+		n := len(editorTools)
+		e.setTool(EditorTool((int(e.Tool) + step + n) % n))
+	}
+	for i, key := range editorToolKeys {
+		if inpututil.IsKeyJustPressed(key) {
+			e.setTool(EditorTool(i))
+		}
+	}
+
+	// Keyboard: arrows move the cursor, Space or Enter paints and Delete or
+	// Backspace erases. Holding Space paints along the way with drag tools.
+	for _, move := range editorCursorKeys {
+		if !keyRepeated(move.key) {
+			continue
+		}
+		next := e.Cursor.Add(move.dir)
+		if next.In(image.Rect(0, 0, e.Map.Width, e.Map.Height)) {
+			e.Cursor = next
+			if ebiten.IsKeyPressed(ebiten.KeySpace) && e.Tool.dragPaints() {
+				e.paint(toCell(e.Cursor))
+			}
+		}
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeySpace) || inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
+		e.paint(toCell(e.Cursor))
+	}
+	if inpututil.IsKeyJustPressed(ebiten.KeyDelete) || inpututil.IsKeyJustPressed(ebiten.KeyBackspace) {
+		e.erase(toCell(e.Cursor))
+	}
+
+	// Mouse: left paints, right erases. Drags only count if they started here, so
+	// the click that opened the editor does not paint.
+	mouse := image.Pt(ebiten.CursorPosition())
+	left := ebiten.IsMouseButtonPressed(ebiten.MouseButtonLeft)
+	if pos, ok := g.editorCellAt(mouse); ok {
+		if mouse != e.lastMouse {
+			e.Cursor = pos
+		}
+		switch {
+		case ebiten.IsMouseButtonPressed(ebiten.MouseButtonRight):
+			e.erase(toCell(pos))
+		case inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft):
+			e.dragging = true
+			e.paint(toCell(pos))
+		case e.dragging && left && e.Tool.dragPaints():
+			e.paint(toCell(pos))
+		}
+	}
+	if !left {
+		e.dragging = false
+	}
+	e.lastMouse = mouse
+}
+
+// editorCellAt returns the board cell under a screen position.
+func (g *Game) editorCellAt(p image.Point) (image.Point, bool) {
+	y := p.Y - HUDHeight(g.cellSize)
+	if p.X < 0 || y < 0 {
+		return image.Point{}, false
+	}
+	pos := image.Pt(p.X/g.cellSize, y/g.cellSize)
+	return pos, pos.In(image.Rect(0, 0, g.Editor.Map.Width, g.Editor.Map.Height))
+}
+
+// resizeEditor changes the size of the map being edited, and the screen with it.
+func (g *Game) resizeEditor(dw, dh int) {
+	g.Editor.resize(dw, dh)
+	g.setGridSize(g.Editor.Map.Width, g.Editor.Map.Height)
+}
+
+// openEditorMap steps through a new map and the installed maps, loading each into
+// the editor. Unsaved changes to the current map are dropped.
+func (g *Game) openEditorMap(step int) {
+	e := g.Editor
+	n := len(g.Maps) + 1
+	e.open = (e.open + step + n) % n
+	e.portalStart = nil
+	e.patrolStart = nil
+	if e.open == 0 {
+		e.Map = &Map{Width: g.defaultGrid.X, Height: g.defaultGrid.Y}
+		e.setStatus("New map")
+	} else {
+		e.Map = g.Maps[e.open-1].clone()
+		if e.Map.Width == 0 {
+			e.Map.Width = g.defaultGrid.X
+		}
+		if e.Map.Height == 0 {
+			e.Map.Height = g.defaultGrid.Y
+		}
+		e.setStatus("Opened %s", e.Map.Name)
+	}
+	g.resizeEditor(0, 0)
+}
+
+// editorOpenName names the map the Open item would load.
+func (g *Game) editorOpenName() string {
+	if g.Editor.open == 0 || g.Editor.open > len(g.Maps) {
+		return "New"
+	}
+	return g.Maps[g.Editor.open-1].Name
+}
+
+// clone returns a deep copy of the map that can be edited freely.
+func (m *Map) clone() *Map {
+	data, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("Failed to copy map %s: %v", m.Name, err)
+		return &Map{Name: m.Name, Width: m.Width, Height: m.Height}
+	}
+	var c Map
+	if err := json.Unmarshal(data, &c); err != nil {
+		log.Printf("Failed to copy map %s: %v", m.Name, err)
+	}
+	c.path = m.path
+	return &c
+}
+
+// saveEditorMap writes the map being edited to the maps folder, over the file it
+// was opened from if there is one, and reloads the installed maps.
+func (g *Game) saveEditorMap() {
+	e := g.Editor
+	m := e.Map
+	if m.Name == "" {
+		e.setStatus("Name the map before saving")
+		return
+	}
+	for _, other := range g.Maps {
+		if other.Name == m.Name && other.path != m.path {
+			e.setStatus("Another map is called %s", m.Name)
+			return
+		}
+	}
+	data, err := m.encode()
+	if err != nil {
+		log.Printf("Failed to encode map: %v", err)
+		e.setStatus("Save failed")
+		return
+	}
+	path := m.path
+	if path == "" {
+		path = newMapPath(m.Name)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		log.Printf("Failed to save map: %v", err)
+		e.setStatus("Save failed")
+		return
+	}
+	m.path = path
+	e.setStatus("Saved %s", filepath.Base(path))
+
+	g.Maps = loadMaps(mapsDir)
+	g.Map = mapByName(g.Maps, g.Settings.Map)
+	for i, installed := range g.Maps {
+		if installed.path == path {
+			e.open = i + 1
+		}
+	}
+}
+
+// newMapPath picks an unused file in the maps folder for a map called name,
+// e.g. "Warp Garden" becomes warp_garden.json.
+func newMapPath(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "_"):
+			b.WriteByte('_')
+		}
+	}
+	base := strings.TrimSuffix(b.String(), "_")
+	if base == "" {
+		base = "map"
+	}
+	path := filepath.Join(mapsDir, base+".json")
+	for i := 2; ; i++ {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return path
+		}
+		path = filepath.Join(mapsDir, fmt.Sprintf("%s_%d.json", base, i))
+	}
+}
+
+// testPlayEditorMap starts a run on the map being edited, unsaved changes included.
+func (g *Game) testPlayEditorMap() {
+	g.leaveDaily()
+	g.testPlay = true
+	g.Map = g.Editor.Map
+	g.startRun()
+}
+
+// leaveTestPlay ends a test run from the editor and restores the map chosen in settings.
+func (g *Game) leaveTestPlay() {
+	if !g.testPlay {
+		return
+	}
+	g.testPlay = false
+	g.Map = mapByName(g.Maps, g.Settings.Map)
+}
+
+// returnToEditor ends a test run and goes back to editing the map.
+func (g *Game) returnToEditor() {
+	g.leaveTestPlay()
+	g.Screens.Switch(&editorScreen{g: g}, TransitionFade)
+}
+
+// editorZoneColor shades food zone cells; editorRouteColor draws enemy routes.
+var (
+	editorZoneColor  = color.NRGBA{R: 0xff, G: 0xd7, B: 0x00, A: 0x50}
+	editorRouteColor = color.NRGBA{R: 0xe5, G: 0x39, B: 0x35, A: 0x90}
+)
+
+// drawEditor draws the map being edited: walls, portals, food zones, enemies and
+// the snake at its spawn, with a cursor over the cell the keyboard paints.
+func (g *Game) drawEditor(screen *ebiten.Image) {
+	e := g.Editor
+	m := e.Map
+	cs := g.cellSize
+	w, h := m.Width*cs, m.Height*cs
+	if g.board == nil {
+		g.board = ebiten.NewImage(w, h)
+	}
+	board := g.board
+	board.Clear()
+
+	g.Theme.DrawBackground(board, w, h, cs)
+	g.Theme.DrawBorder(board, w, h, cs)
+	for x := 1; x < m.Width; x++ {
+		vector.StrokeLine(board, float32(x*cs), 0, float32(x*cs), float32(h), 1, color.RGBA{A: 0x30}, false)
+	}
+	for y := 1; y < m.Height; y++ {
+		vector.StrokeLine(board, 0, float32(y*cs), float32(w), float32(y*cs), 1, color.RGBA{A: 0x30}, false)
+	}
+
+	for _, c := range m.FoodZones {
+		vector.DrawFilledRect(board, float32(c[0]*cs), float32(c[1]*cs), float32(cs), float32(cs), editorZoneColor, false)
+	}
+	for _, c := range m.Walls {
+		g.Theme.DrawHazard(board, c.point(), cs, 1)
+	}
+	for i, p := range m.Portals {
+		c := portalColors[i%len(portalColors)]
+		g.Theme.DrawPortal(board, p.A.point(), cs, c)
+		g.Theme.DrawPortal(board, p.B.point(), cs, c)
+	}
+	if e.portalStart != nil {
+		g.Theme.DrawPortal(board, e.portalStart.point(), cs, portalColors[len(m.Portals)%len(portalColors)])
+	}
+	for _, d := range m.Movers {
+		drawRoute(board, d, cs)
+	}
+	for _, mv := range e.movers {
+		g.Theme.DrawMover(board, moverSprites[mv.Kind], mv.Pos, cs)
+	}
+	g.Renderer.DrawSnake(board, e.snake, 0)
+
+	cx, cy := float32(e.Cursor.X*cs), float32(e.Cursor.Y*cs)
+	vector.StrokeRect(board, cx+0.5, cy+0.5, float32(cs)-1, float32(cs)-1, 1, g.Theme.Palette.Accent, false)
+
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(0, float64(HUDHeight(cs)))
+	screen.DrawImage(board, op)
+
+	hudHeight := HUDHeight(cs)
+	g.Theme.DrawHUDBar(screen, g.screenWidth, hudHeight, cs)
+	g.UI.DrawEditorHUD(screen, g.screenWidth, hudHeight, render.EditorStats{
+		Tool:   editorTools[e.Tool],
+		Name:   m.Name,
+		Width:  m.Width,
+		Height: m.Height,
+		Status: e.status,
+	})
+}
+
+// drawRoute draws where an enemy goes: a patrol's path through its waypoints,
+// across first and then up or down as it walks, and a line out of a bouncer in the
+// direction it sets off.
+func drawRoute(board *ebiten.Image, d moverDef, cs int) {
+	center := func(c cell) (float32, float32) {
+		return float32(c[0]*cs + cs/2), float32(c[1]*cs + cs/2)
+	}
+	line := func(a, b cell) {
+		x0, y0 := center(a)
+		x1, y1 := center(b)
+		vector.StrokeLine(board, x0, y0, x1, y1, 2, editorRouteColor, true)
+	}
+	if d.Kind == "bouncer" {
+		line(d.Pos, cell{d.Pos[0] + d.Dir[0], d.Pos[1] + d.Dir[1]})
+		return
+	}
+	leg := func(a, b cell) {
+		corner := cell{b[0], a[1]}
+		line(a, corner)
+		line(corner, b)
+	}
+	for i := 1; i < len(d.Path); i++ {
+		leg(d.Path[i-1], d.Path[i])
+	}
+	if d.Loop && len(d.Path) > 2 {
+		leg(d.Path[len(d.Path)-1], d.Path[0])
+	}
+}
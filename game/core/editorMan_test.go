@@ -0,0 +1,78 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+// click is one use of an editor tool on a cell.
+type click struct {
+	tool EditorTool
+	at   cell
+}
+
+func TestEditorMovers(t *testing.T) {
+	tests := []struct {
+		name   string
+		clicks []click
+		want   []moverDef
+	}{
+		{"patrol standing still", []click{{ToolPatrol, cell{3, 3}}, {ToolPatrol, cell{3, 3}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{3, 3}}}}},
+		{"patrol back and forth", []click{{ToolPatrol, cell{3, 3}}, {ToolPatrol, cell{9, 3}}, {ToolPatrol, cell{9, 8}}, {ToolPatrol, cell{9, 8}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{3, 3}, {9, 3}, {9, 8}}}}},
+		{"looping patrol", []click{{ToolPatrol, cell{3, 3}}, {ToolPatrol, cell{9, 3}}, {ToolPatrol, cell{9, 8}}, {ToolPatrol, cell{3, 3}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{3, 3}, {9, 3}, {9, 8}}, Loop: true}}},
+		{"second patrol after finishing", []click{{ToolPatrol, cell{1, 1}}, {ToolPatrol, cell{5, 1}}, {ToolPatrol, cell{5, 1}}, {ToolPatrol, cell{2, 6}}, {ToolPatrol, cell{2, 9}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{1, 1}, {5, 1}}}, {Kind: "patrol", Path: []cell{{2, 6}, {2, 9}}}}},
+		{"switching tools ends the path", []click{{ToolPatrol, cell{1, 1}}, {ToolWall, cell{4, 4}}, {ToolPatrol, cell{6, 1}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{1, 1}}}, {Kind: "patrol", Path: []cell{{6, 1}}}}},
+		{"waypoint on a wall", []click{{ToolWall, cell{5, 1}}, {ToolPatrol, cell{1, 1}}, {ToolPatrol, cell{5, 1}}, {ToolPatrol, cell{5, 2}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{1, 1}, {5, 2}}}}},
+		{"erasing the patrol being laid", []click{{ToolPatrol, cell{1, 1}}, {ToolErase, cell{1, 1}}, {ToolPatrol, cell{4, 4}}},
+			[]moverDef{{Kind: "patrol", Path: []cell{{4, 4}}}}},
+		{"bouncer", []click{{ToolBouncer, cell{7, 7}}},
+			[]moverDef{{Kind: "bouncer", Pos: cell{7, 7}, Dir: cell{1, 1}}}},
+		{"bouncer turned", []click{{ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}},
+			[]moverDef{{Kind: "bouncer", Pos: cell{7, 7}, Dir: cell{-1, 1}}}},
+		{"bouncer turned all the way round", []click{{ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}},
+			{ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}, {ToolBouncer, cell{7, 7}}},
+			[]moverDef{{Kind: "bouncer", Pos: cell{7, 7}, Dir: cell{1, 1}}}},
+		{"bouncer over a wall", []click{{ToolWall, cell{2, 2}}, {ToolBouncer, cell{2, 2}}},
+			[]moverDef{{Kind: "bouncer", Pos: cell{2, 2}, Dir: cell{1, 1}}}},
+		{"wall over an enemy", []click{{ToolBouncer, cell{2, 2}}, {ToolWall, cell{2, 2}}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewEditor(12, 12)
+			for _, c := range tt.clicks {
+				if e.Tool != c.tool {
+					e.setTool(c.tool)
+				}
+				e.paint(c.at)
+			}
+			if !sameMovers(e.Map.Movers, tt.want) {
+				t.Fatalf("movers = %+v, want %+v", e.Map.Movers, tt.want)
+			}
+			if len(e.movers) != len(tt.want) {
+				t.Errorf("%d enemies drawn, want %d", len(e.movers), len(tt.want))
+			}
+			for i, mv := range e.movers {
+				if pos := tt.want[i].startCell().point(); mv.Pos != pos {
+					t.Errorf("enemy %d drawn at %v, want %v", i, mv.Pos, pos)
+				}
+			}
+
+			// The map saves and loads back as it was built.
+			e.Map.Name = "Built"
+			if got := roundTrip(t, e.Map); !sameMovers(got.Movers, e.Map.Movers) {
+				t.Errorf("loaded movers %+v, want %+v", got.Movers, e.Map.Movers)
+			}
+		})
+	}
+}
+
+// sameMovers compares lists of enemies, counting no list as an empty one.
+func sameMovers(a, b []moverDef) bool {
+	return len(a) == 0 && len(b) == 0 || reflect.DeepEqual(a, b)
+}
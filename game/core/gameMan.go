@@ -1,26 +1,36 @@
 package core
 
 import (
+	"fmt"
 	"image"
+	"image/color"
 	"log"
+	"math/rand"
 	"os"
 	"snakeGame/game/audio"
 	"snakeGame/game/entities"
 	"snakeGame/game/render"
 	"snakeGame/game/ui"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/inpututil"
 )
 
-// GameScreen represents the current screen/state of the game.
-type GameScreen int
+// hudRows is the height of the HUD bar above the board, in grid cells.
+const hudRows = 2
 
+// HUDHeight returns the pixel height of the HUD bar for the given cell size.
+func HUDHeight(cellSize int) int {
+	return hudRows * cellSize
+}
+
+// countdownSeconds is how long the 3-2-1 countdown lasts when resuming from pause.
+const countdownSeconds = 3
+
+// Death sequence timing, in frames.
 const (
-	ScreenTitle GameScreen = iota
-	ScreenPlaying
-	ScreenSettings
-	ScreenGameOver
+	deathSegmentFrames = 4  // frames between each segment collapsing
+	deathHoldFrames    = 30 // pause after the collapse before the menu appears
 )
 
 // Game implements the ebiten.Game interface and holds the game state.
@@ -35,108 +45,166 @@ type Game struct {
 	SpriteManager             *render.SpriteManager
 	screenWidth, screenHeight int                       // screen size in pixels for rendering
 	gridWidth, gridHeight     int                       // grid size in cells (play field dimensions)
+	defaultGrid               image.Point               // grid size for boards that do not set their own
 	cellSize                  int                       // pixel size of one grid cell
 	Snake                     *entities.SnakeController // the player-controlled Snake
-	Food                      *entities.Food            // the current food item on the board
+	Foods                     []*entities.Food          // food items on the board; always includes an apple
+	PowerUpItems              []*entities.PowerUp       // power-ups on the board waiting to be collected
+	PowerUps                  *PowerUpManager           // effects of collected power-ups
+	Mode                      *Mode                     // active game mode
+	Scoring                   *ScoreManager             // combo and bonus scoring for the mode
+	Hazards                   *HazardManager            // obstacles and shrinking walls in survival
+	Maps                      []*Map                    // installed board layouts
+	Map                       *Map                      // board layout of the current run; nil = open board
+	Movers                    []*entities.Mover         // enemies moving on the board
+	Portals                   []entities.Portal         // linked cell pairs from the map
+	foodZone                  map[image.Point]bool      // cells food keeps to, from the map; nil = anywhere
+	Editor                    *Editor                   // level editor state, kept between visits
+	testPlay                  bool                      // the run is a test of the map being edited
+	Daily                     *DailyChallenge           // today's challenge while one is being played
+	dailyOfficial             bool                      // the current daily run is the day's recorded attempt
+	Inputs                    *InputLog                 // direction changes this run, for replays
+	Seed                      int64                     // seed of the current run
+	Ghosts                    *GhostStore               // personal best runs to race
+	Ghost                     *Ghost                    // personal best raced in the current run; nil = none
+	rewind                    rewindBuffer              // snapshots before recent moves in practice mode
+	rewinding                 int                       // frames the rewind key has been held; 0 = not rewinding
+	rewound                   bool                      // the run has been rewound and is kept out of high scores
+	apples                    int                       // apples eaten this run
+	deathCause                deathCause                // what ended the run, once it is over
+	deathPos                  image.Point               // cell where the run ended
+	rng                       *rand.Rand                // all gameplay randomness; seeded per run
+	rngSource                 *countingSource           // rng's source, counting draws so saves can restore it
+	hasSave                   bool                      // a saved run is waiting to be continued
 	gameOver                  bool                      // whether the game is over
 	frameCount                int                       // Tracks number of frames since last move
 	frameDelay                int                       // Delay between moves (in frames)
 	showRetry                 bool                      // used to toggle retry prompt visibility
 	SoundMan                  *audio.SoundManager
-	CurrentScreen             GameScreen
-	menuSelected              int // 0 = Play Game, 1 = Settings
-	gameOverSelected          int // 0 = Play Again, 1 = Main Menu, 2 = Exit Game
+	Effects                   *render.ParticleManager
+	board                     *ebiten.Image // off-screen playfield, shaken as a whole by effects
+	Settings                  *SettingsManager
+	Profile                   *ProfileManager
+	Difficulties              []*Difficulty // available difficulty presets
+	Difficulty                *Difficulty   // difficulty of the current run
+	Themes                    []*ui.Theme   // installed themes
+	Theme                     *ui.Theme     // active theme for board graphics and music
+	Screens                   *ScreenManager
+	titleMenu                 *render.Menu
+	settingsMenu              *render.Menu
+	gameOverMenu              *render.Menu
+	pauseMenu                 *render.Menu
+	highScoresMenu            *render.Menu
+	statsMenu                 *render.Menu
+	statsPage                 statsPage // page shown on the statistics screen
+	statsLevel                int       // level whose death heatmap is shown
+	statsStatus               string    // result of the last export
+	editorMenu                *render.Menu
+	deathFrame                int // frames elapsed in the death sequence
 }
 
 // NewGame initializes a new game state with a Snake and an initial food.
-func NewGame(startPosition image.Point, gridWidth, gridHeight, cellSize int) *Game {
+// themes lists the installed themes; the one chosen in settings becomes active.
+func NewGame(startPosition image.Point, gridWidth, gridHeight, cellSize int, themes []*ui.Theme) *Game {
 	snake := entities.NewSnakeController(startPosition, gridWidth, gridHeight)
 	log.Println("Attempting to load sprite sheet...")
 
 	sprites := render.NewSpriteManager(cellSize)
+	uiMan := render.NewUIManager()
 
 	log.Println("SpriteManager initialized")
 	g := &Game{
-		State:            NewStateManager(),
-		Speed:            NewSpeedManager(),
-		UI:               render.NewUIManager(),
-		Renderer:         render.NewRenderer(sprites),
-		SpriteManager:    sprites,
-		Snake:            snake,
-		Food:             entities.NewFood(snake, gridWidth, gridHeight),
-		gridWidth:        gridWidth,
-		gridHeight:       gridHeight,
-		cellSize:         cellSize,
-		screenWidth:      gridWidth * cellSize,
-		screenHeight:     gridHeight * cellSize,
-		gameOver:         false,
-		showRetry:        false,
-		frameDelay:       20,
-		SoundMan:         audio.NewSoundManager(),
-		CurrentScreen:    ScreenTitle,
-		menuSelected:     0,
-		gameOverSelected: 0,
+		State:         NewStateManager(),
+		Speed:         NewSpeedManager(),
+		UI:            uiMan,
+		Renderer:      render.NewRenderer(sprites),
+		SpriteManager: sprites,
+		Snake:         snake,
+		PowerUps:      NewPowerUpManager(),
+		Hazards:       NewHazardManager(),
+		Inputs:        &InputLog{},
+		rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+		gridWidth:     gridWidth,
+		gridHeight:    gridHeight,
+		defaultGrid:   image.Pt(gridWidth, gridHeight),
+		cellSize:      cellSize,
+		screenWidth:   gridWidth * cellSize,
+		screenHeight:  gridHeight*cellSize + HUDHeight(cellSize),
+		gameOver:      false,
+		showRetry:     false,
+		frameDelay:    20,
+		SoundMan:      audio.NewSoundManager(),
+		Effects:       render.NewParticleManager(uiMan),
+		Settings:      NewSettingsManager(),
+		Profile:       NewProfileManager(),
+		Themes:        themes,
+		Screens:       NewScreenManager(uiMan),
+		hasSave:       saveExists(),
+		Ghosts:        NewGhostStore(),
 	}
 
-	g.loadSounds()
+	g.UI.SetScreenSize(g.screenWidth, g.screenHeight)
+	g.setTheme(ui.ThemeByName(themes, g.Settings.Theme))
+	g.applySkin()
+	g.Difficulties = loadDifficulties(difficultiesFile)
+	g.setDifficulty(difficultyByName(g.Difficulties, g.Settings.Difficulty))
+	g.Renderer.Interpolate = g.Settings.Smooth
+	g.Effects.Enabled = g.Settings.Effects
+	g.SoundMan.SetVolume(g.Settings.Volume)
+	g.Maps = loadMaps(mapsDir)
+	g.Map = mapByName(g.Maps, g.Settings.Map)
+	g.Mode = modeByName(g.Settings.Mode)
+	g.Scoring = NewScoreManager(g.Mode.Scoring)
+	g.buildMenus()
+	g.Screens.Switch(&titleScreen{g: g}, TransitionNone)
 	return g
 }
 
 // Update advances the game state by one frame (called ~60 times per second by Ebiten).
+// Closing the window saves the run in progress first.
 func (g *Game) Update() error {
-	if g.CurrentScreen == ScreenTitle {
-		// Handle menu navigation
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
-			g.menuSelected = (g.menuSelected + 1) % 2 // wrap
-		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-			g.menuSelected = (g.menuSelected + 1) % 2 // wrap
-		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-			if g.menuSelected == 0 {
-				g.CurrentScreen = ScreenPlaying
-			} else if g.menuSelected == 1 {
-				g.CurrentScreen = ScreenSettings
-			}
+	if ebiten.IsWindowBeingClosed() {
+		if g.canSave() {
+			g.saveGame()
 		}
+		return ebiten.Termination
+	}
+	return g.Screens.Update()
+}
+
+// updatePlaying advances gameplay by one frame while the playing screen is on top.
+func (g *Game) updatePlaying() error {
+	// Pausing pushes an overlay that stops this screen from updating.
+	if g.handlePauseToggle() {
 		return nil
 	}
-	if g.CurrentScreen == ScreenGameOver {
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) {
-			g.gameOverSelected = (g.gameOverSelected + 2) % 3 // wrap up
-		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) {
-			g.gameOverSelected = (g.gameOverSelected + 1) % 3 // wrap down
-		}
-		if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-			switch g.gameOverSelected {
-			case 0: // Play Again
-				g.resetGame()
-				g.CurrentScreen = ScreenPlaying
-			case 1: // Main Menu
-				g.resetGame()
-				g.CurrentScreen = ScreenTitle
-			case 2: // Exit Game
-				os.Exit(0)
-			}
-		}
+
+	// Effects keep animating through the death sequence.
+	g.Effects.Update()
+
+	// Practice mode can rewind, even out of the death sequence.
+	if g.updateRewind() {
 		return nil
 	}
-	// Pause/resume toggle should still work while game is running
-	g.handlePauseToggle()
 
-	// Allow reset via Enter or mouse click even if game is over
+	// Play out the death sequence, then switch to the game-over menu.
 	if g.State.GameOver {
-		g.CurrentScreen = ScreenGameOver
-		g.gameOverSelected = 0
+		g.deathFrame++
+		if g.deathFrame < g.deathDuration() {
+			return nil
+		}
+		g.finishRun()
 		return nil
 	}
 
-	// Handle background music play/pause
-	if g.State.Paused {
-		g.SoundMan.PauseLoopingSound("bgm")
-	} else {
-		g.SoundMan.PlayLoopingSound("bgm")
+	// When time runs out the board holds briefly before the results appear.
+	if g.State.TimeUp {
+		g.deathFrame++
+		if g.deathFrame < deathHoldFrames {
+			return nil
+		}
+		g.finishRun()
+		return nil
 	}
 
 	// Exit early if paused or game is over
@@ -147,99 +215,341 @@ func (g *Game) Update() error {
 	// Handle user input for Snake direction.
 	g.handleInput()
 
-	// Frame throttling — only update logic every N frames
-	if !g.Speed.ShouldUpdate() {
-		return nil
+	// Fixed timestep: run as many moves as the real time since the last frame allows.
+	steps, dt := g.Speed.Advance()
+	g.State.Elapsed += dt
+	if g.Ghost != nil {
+		g.Ghost.Advance(dt)
+	}
+	if g.Mode.TimeLimit > 0 {
+		g.State.TimeLeft -= dt
+		if g.State.TimeLeft <= 0 {
+			g.timeUp()
+			return nil
+		}
+	}
+	for i := 0; i < steps && g.State.IsRunning(); i++ {
+		g.tick()
+	}
+	return nil
+}
+
+// tick advances the simulation by exactly one move.
+func (g *Game) tick() {
+	if g.Mode.Rewind {
+		g.rewind.Push(g.captureRun())
 	}
+	g.Inputs.Record(g.State.Ticks, g.Snake.PendingDir)
 
 	// Calculate the Snake's new head position based on current direction.
 	g.Snake.ApplyPendingDirection(g.gridWidth, g.gridHeight)
 	newHead := g.Snake.NextHeadPosition()
 
+	// A shield turns a wall hit into wrapping around to the opposite edge.
+	jumped := false
+	if g.outOfBounds(newHead) && g.PowerUps.UseShield() {
+		newHead = g.wrap(newHead)
+		jumped = true
+		g.Effects.Flash(6)
+	}
+
+	// Entering a portal puts the head on the linked cell, still facing the same way.
+	if exit, ok := g.portalExit(newHead); ok {
+		newHead = exit
+		jumped = true
+	}
+
 	// Collision check: Wall boundaries || Snake runs into itself.
-	if g.checkCollision(newHead) {
-		g.State.SetGameOver()
-		return nil
+	if cause := g.checkCollision(newHead); cause != causeNone {
+		g.die(cause, newHead)
+		return
+	}
+
+	// Running into an enemy.
+	if m := g.moverAt(newHead); m != nil && g.hitMover(m) {
+		return
+	}
+
+	// Survival scores by moves survived rather than by food.
+	g.State.Ticks++
+	if every := g.Mode.TickScoreEvery; every > 0 && g.State.Ticks%every == 0 {
+		g.State.AddScore(1)
+		g.Speed.AdjustSpeedByLevel(g.State.Level)
+	}
+
+	// Timed effects and item lifetimes count down in moves.
+	g.updateHazards()
+	// The board may have closed in on the snake or on the cell it is moving to.
+	if g.State.GameOver {
+		return
+	}
+	if g.outOfBounds(newHead) {
+		g.die(causeWall, newHead)
+		return
+	}
+	g.Speed.TickModifiers()
+	g.PowerUps.Tick()
+	g.Scoring.Tick()
+	g.expireFood()
+	g.expirePowerUps()
+	if g.PowerUps.Active(entities.PowerMagnet) {
+		g.pullFood()
+	}
+
+	// The head moves every tick, also onto food, so it never stops for a bite; a
+	// snake that eats grows on its next move. A head that jumped cannot step there
+	// by direction.
+	if jumped {
+		g.Snake.MoveTo(newHead)
+	} else {
+		g.Snake.MoveForward()
+	}
+
+	if item := g.powerUpAt(newHead); item != nil {
+		g.collectPowerUp(item)
 	}
 
 	// Check if food is eaten.
-	if g.checkFoodEaten(newHead) {
-		g.handleFoodEaten()
+	if food := g.foodAt(newHead); food != nil {
+		g.handleFoodEaten(food)
 		// Play apple bite sound
 		g.SoundMan.PlaySound("bite")
-	} else {
-		g.Snake.MoveForward()
 	}
 
-	return nil // No error, continue game.
+	// Enemies move after the snake and may run into its head.
+	if !g.State.GameOver {
+		g.updateMovers()
+	}
+}
+
+// die ends the run with the crash effects; the death sequence plays out next. The
+// cause and the cell where it happened go into the lifetime statistics.
+func (g *Game) die(cause deathCause, pos image.Point) {
+	g.State.SetGameOver()
+	g.deathCause, g.deathPos = cause, pos
+	g.deathFrame = 0
+	g.SoundMan.PauseLoopingSound("bgm")
+	g.SoundMan.PlaySound("crash")
+	g.Effects.Shake(20, 3)
+	g.Effects.Flash(12)
+}
+
+// finishRun records the final score and shows the game-over menu. Test runs of
+// a map in the editor and rewound runs are not recorded, in the statistics either,
+// nor raced as ghosts.
+func (g *Game) finishRun() {
+	if !g.testPlay && !g.rewound {
+		g.Profile.RecordRun(g.runRecord())
+		// Only the first attempt at a daily challenge counts on its table.
+		if g.Daily == nil || g.dailyOfficial {
+			g.Profile.RecordScore(g.Mode.ScoreTable, g.State.Score)
+		}
+		g.recordGhost()
+	}
+	if g.Daily != nil && g.dailyOfficial {
+		g.recordDaily()
+	}
+	g.Screens.Push(&gameOverScreen{g: g}, TransitionFade)
+}
+
+// timeUp ends a timed run when the clock reaches zero.
+func (g *Game) timeUp() {
+	g.State.TimeLeft = 0
+	g.State.SetTimeUp()
+	g.deathFrame = 0
+	g.SoundMan.PauseLoopingSound("bgm")
+	g.Effects.Flash(12)
 }
 
 // Draw renders the game state to the screen (called every frame after Update).
 func (g *Game) Draw(screen *ebiten.Image) {
-	if g.CurrentScreen == ScreenTitle {
-		g.UI.DrawTitleScreen(screen, g.screenWidth, g.screenHeight, g.menuSelected)
-		return
+	g.UI.ClearText()
+	g.Screens.Draw(screen)
+}
+
+// DrawFinalScreen scales the frame up to the window and draws the UI text over it
+// at the window's resolution.
+func (g *Game) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	g.UI.DrawFinalScreen(screen, offscreen, geoM)
+}
+
+// runStats collects the numbers shown on the game-over screen.
+func (g *Game) runStats() render.RunStats {
+	return render.RunStats{
+		Score:     g.State.Score,
+		Level:     g.State.Level,
+		Length:    g.Snake.Length(),
+		BestScore: g.bestScore(),
+		Breakdown: g.Scoring.Breakdown.Lines(),
+		Note:      g.runNote(),
 	}
-	if g.CurrentScreen == ScreenGameOver {
-		g.SoundMan.PauseLoopingSound("bgm")
-		g.UI.DrawGameOverOverlay(screen, g.screenWidth, g.screenHeight, g.gameOverSelected)
-		return
+}
+
+// bestScore returns the top score on the current mode's high score table.
+func (g *Game) bestScore() int {
+	if scores := g.Profile.ScoresFor(g.Mode.ScoreTable); len(scores) > 0 {
+		return scores[0]
 	}
-	if g.CurrentScreen == ScreenSettings {
-		g.UI.DrawSettingsScreen(screen, g.screenWidth, g.screenHeight)
-		return
+	return 0
+}
+
+// runNote flags a rewound run, whose score is not recorded.
+func (g *Game) runNote() string {
+	if g.rewound {
+		return "Rewound - score not recorded"
 	}
+	return ""
+}
 
+// drawBoard draws the playfield: background, border, snake and food. While the
+// death sequence runs the snake collapses from the tail towards a flashing head.
+func (g *Game) drawBoard(screen *ebiten.Image) {
 	screenWidth := g.gridWidth * g.cellSize
 	screenHeight := g.gridHeight * g.cellSize
 
+	// The board is drawn off-screen first so effects can shake it as a whole.
+	if g.board == nil {
+		g.board = ebiten.NewImage(screenWidth, screenHeight)
+	}
+	g.board.Clear()
+
 	// Draw theme
-	ui.DrawBackground(screen, screenWidth, screenHeight, g.cellSize)
-	ui.DrawBorder(screen, screenWidth, screenHeight, g.cellSize)
+	g.Theme.DrawBackground(g.board, screenWidth, screenHeight, g.cellSize)
+	g.Theme.DrawBorder(g.board, screenWidth, screenHeight, g.cellSize)
+
+	// Draw the Snake, with the ghost of the personal best underneath.
+	g.drawGhost()
+	if g.State.GameOver {
+		visible := g.Snake.Length() - g.deathFrame/deathSegmentFrames
+		headVisible := (g.deathFrame/6)%2 == 0
+		g.Renderer.DrawSnakeDying(g.board, g.Snake, visible, headVisible)
+	} else {
+		g.Renderer.DrawSnake(g.board, g.Snake, g.Speed.Progress())
+	}
 
-	// Draw the Snake.
-	g.Renderer.DrawSnake(screen, g.Snake)
+	g.drawHazards(g.board)
+	g.drawPortals(g.board)
 
-	op := &ebiten.DrawImageOptions{}
-	op.GeoM.Translate(float64(g.Food.Pos.X*g.cellSize), float64(g.Food.Pos.Y*g.cellSize))
+	// Draw the food; items about to expire blink.
+	for _, food := range g.Foods {
+		if food.Expiring() && food.TicksLeft%2 == 0 {
+			continue
+		}
+		g.Theme.DrawFood(g.board, food.Spec().Sprite, food.Pos, g.cellSize)
+	}
+	for _, item := range g.PowerUpItems {
+		if item.Expiring() && item.TicksLeft%2 == 0 {
+			continue
+		}
+		g.Theme.DrawPowerUp(g.board, item.Spec().Sprite, item.Pos, g.cellSize)
+	}
+	g.drawMovers(g.board)
 
-	// Draw the food.
-	ui.DrawFood(screen, g.Food.Pos, g.cellSize)
+	// The board sits below the HUD bar.
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(g.Effects.ShakeOffset())
+	op.GeoM.Translate(0, float64(HUDHeight(g.cellSize)))
+	screen.DrawImage(g.board, op)
+}
 
-	if g.State.Paused {
-		g.UI.DrawPauseOverlay(screen, g.screenWidth, g.screenHeight)
+// drawHUD draws the status bar above the board.
+func (g *Game) drawHUD(screen *ebiten.Image) {
+	hudHeight := HUDHeight(g.cellSize)
+	g.Theme.DrawHUDBar(screen, g.screenWidth, hudHeight, g.cellSize)
+	g.UI.DrawHUD(screen, g.screenWidth, hudHeight, render.HUDStats{
+		Score:         g.State.Score,
+		BestScore:     g.bestScore(),
+		Level:         g.State.Level,
+		LevelProgress: g.State.LevelProgress(),
+		Length:        g.Snake.Length(),
+		Elapsed:       g.State.Elapsed,
+		MovesPerSec:   g.Speed.MovesPerSecond(),
+		Timed:         g.Mode.TimeLimit > 0,
+		TimeLeft:      g.State.TimeLeft,
+	})
+
+	// Running power-up timers sit in the board's top-left corner, inside the border.
+	timers := g.PowerUps.Timers(g.Theme)
+	if combo, ok := g.Scoring.ComboTimer(); ok {
+		timers = append([]render.HUDTimer{combo}, timers...)
 	}
+	g.UI.DrawTimers(screen, float64(g.cellSize), float64(hudHeight+g.cellSize), g.cellSize, timers)
 
-	// Overlay game status text.
-	if g.State.GameOver {
-		g.SoundMan.PauseLoopingSound("bgm")
-		g.UI.DrawGameOverOverlay(screen, g.screenWidth, g.screenHeight, g.gameOverSelected)
-	} else {
-		g.UI.DrawStatus(screen, g.State.Score, g.State.Level)
-		if g.State.Paused {
-			g.UI.DrawPauseOverlay(screen, g.screenWidth, g.screenHeight)
-		}
+	if g.rewinding > 0 {
+		g.UI.DrawRewind(screen, g.screenWidth, g.screenHeight)
 	}
 }
 
+// deathDuration is the total length of the death sequence in frames: one step per
+// segment collapsing, then a short hold on the empty board.
+func (g *Game) deathDuration() int {
+	return g.Snake.Length()*deathSegmentFrames + deathHoldFrames
+}
+
+// resetGame sets up a fresh run of the current mode. Daily challenges reuse the
+// day's seed so every attempt sees the same board, and so do runs racing a ghost.
 func (g *Game) resetGame() {
-	start := image.Pt(g.gridWidth/2, g.gridHeight/2)
-	g.Snake = entities.NewSnakeController(start, g.gridWidth, g.gridHeight)
-	g.Food = entities.NewFood(g.Snake, g.gridWidth, g.gridHeight)
+	seed := time.Now().UnixNano()
+	if g.Daily != nil {
+		seed = g.Daily.Seed
+	} else if run := g.raceRun(); run != nil {
+		seed = run.Seed
+	}
+	g.resetRun(seed)
+}
+
+// resetRun sets up a fresh run of the current mode on the given seed.
+func (g *Game) resetRun(seed int64) {
+	g.seedRun(seed, 0)
+
+	g.setGridSize(g.runGridSize())
+	start, dir := g.activeMap().start(g.gridWidth, g.gridHeight)
+	g.Snake = entities.NewSnakeControllerFacing(start, dir, g.gridWidth, g.gridHeight)
+	g.Inputs.Reset(g.Snake.PendingDir)
+	g.rewind.Clear()
+	g.rewound = false
+	g.apples = 0
 	g.State.Reset()
+	g.State.TimeLeft = g.Mode.TimeLimit
+	g.PowerUpItems = nil
+	g.PowerUps.Reset()
+	g.Hazards.Reset()
+	g.applyMap()
+	g.placeRocks(g.Mode.Rocks)
+	g.Foods = nil
+	g.spawnFood(entities.FoodApple)
+	g.Scoring.Rule = g.Mode.Scoring
+	g.Scoring.Reset()
+	g.Speed.ClearModifiers()
+	g.Speed.AdjustSpeedByLevel(g.State.Level)
+	g.Speed.Reset()
+	g.Effects.Clear()
 	g.frameCount = 0
 	g.showRetry = false
-	g.loadSounds()
-	// Restart background music from the beginning
+	g.deathFrame = 0
+	g.syncGhost()
+	// Restart background music from the beginning; the playing screen starts it.
 	if g.SoundMan != nil {
 		if player, ok := g.SoundMan.LoopingPlayer("bgm"); ok {
+			player.Pause()
 			player.Rewind()
-			player.Play()
 		}
 	}
-	g.CurrentScreen = ScreenTitle
-	g.menuSelected = 0
-	g.gameOverSelected = 0
+}
+
+// setGridSize resizes the board, e.g. for a map or the editor. The logical screen
+// follows and Ebiten scales it to fit the window.
+func (g *Game) setGridSize(w, h int) {
+	if w == g.gridWidth && h == g.gridHeight {
+		return
+	}
+	g.gridWidth, g.gridHeight = w, h
+	g.screenWidth = w * g.cellSize
+	g.screenHeight = h*g.cellSize + HUDHeight(g.cellSize)
+	g.board = nil
+	if g.UI != nil {
+		g.UI.SetScreenSize(g.screenWidth, g.screenHeight)
+	}
 }
 
 // Layout returns the internal screen size (logical resolution) for the game.
@@ -248,15 +558,188 @@ func (g *Game) Layout(_, _ int) (int, int) {
 	return g.screenWidth, g.screenHeight
 }
 
-func (g *Game) checkFoodEaten(newHead image.Point) bool {
-	return newHead == g.Food.Pos
+// foodAt returns the food item at pos, or nil if the cell is empty.
+func (g *Game) foodAt(pos image.Point) *entities.Food {
+	for _, food := range g.Foods {
+		if food.Pos == pos {
+			return food
+		}
+	}
+	return nil
+}
+
+// cellBlocked reports whether a new item may not be placed at pos.
+func (g *Game) cellBlocked(pos image.Point) bool {
+	return g.outOfBounds(pos) || g.Snake.Occupies(pos) || g.foodAt(pos) != nil ||
+		g.powerUpAt(pos) != nil || g.hazardAt(pos) != nil || g.moverAt(pos) != nil || g.portalAt(pos)
+}
+
+// spawnFood places a new food item of the given kind on a free cell, inside the
+// map's food zones while they have room. Nothing spawns on a full board.
+func (g *Game) spawnFood(kind entities.FoodKind) {
+	blocked := g.cellBlocked
+	if g.foodZoneOpen() {
+		blocked = func(pos image.Point) bool { return !g.foodZone[pos] || g.cellBlocked(pos) }
+	}
+	if food, ok := entities.NewFoodOfKind(g.rng, kind, g.gridWidth, g.gridHeight, blocked); ok {
+		g.Foods = append(g.Foods, food)
+	}
+}
+
+// removeFood takes a food item off the board.
+func (g *Game) removeFood(food *entities.Food) {
+	for i, f := range g.Foods {
+		if f == food {
+			g.Foods = append(g.Foods[:i], g.Foods[i+1:]...)
+			return
+		}
+	}
 }
 
-func (g *Game) handleFoodEaten() {
-	g.Snake.Grow()
-	g.State.IncreaseScore()
-	g.Speed.AdjustDelayByLevel(g.State.Level)
-	g.Food = entities.NewFood(g.Snake, g.gridWidth, g.gridHeight)
+// expireFood counts down food lifetimes and removes items that have run out.
+func (g *Game) expireFood() {
+	kept := g.Foods[:0]
+	for _, food := range g.Foods {
+		if !food.Tick() {
+			kept = append(kept, food)
+		}
+	}
+	g.Foods = kept
+}
+
+// Food effect tuning.
+const (
+	berryShrink      = 3   // segments removed by a berry
+	minSnakeLength   = 3   // berries never shrink the snake below this
+	pepperFactor     = 1.5 // speed multiplier while a pepper is active
+	mushroomFactor   = 0.6 // speed multiplier while a mushroom is active
+	speedEffectMoves = 30  // how long pepper and mushroom effects last, in moves
+)
+
+// foodColors tints the particle burst for each kind of food.
+var foodColors = map[entities.FoodKind]color.RGBA{
+	entities.FoodApple:       {R: 0xe0, G: 0x30, B: 0x30, A: 0xff},
+	entities.FoodGoldenApple: {R: 0xff, G: 0xd7, B: 0x00, A: 0xff},
+	entities.FoodBerry:       {R: 0x6a, G: 0x2c, B: 0x9e, A: 0xff},
+	entities.FoodPepper:      {R: 0xff, G: 0x60, B: 0x20, A: 0xff},
+	entities.FoodMushroom:    {R: 0x8d, G: 0x5a, B: 0x3b, A: 0xff},
+	entities.FoodPoison:      {R: 0x4c, G: 0xaf, B: 0x50, A: 0xff},
+}
+
+func (g *Game) handleFoodEaten(food *entities.Food) {
+	// Effects are centered on the eaten food's cell, in screen space below the HUD.
+	cx := float64(food.Pos.X*g.cellSize + g.cellSize/2)
+	cy := float64(food.Pos.Y*g.cellSize + g.cellSize/2 + HUDHeight(g.cellSize))
+	level := g.State.Level
+	spec := food.Spec()
+	g.removeFood(food)
+
+	switch food.Kind {
+	case entities.FoodApple, entities.FoodGoldenApple:
+		g.Snake.Grow()
+		g.apples++
+	case entities.FoodBerry:
+		g.Snake.Shrink(berryShrink, minSnakeLength)
+	case entities.FoodPepper:
+		g.Speed.SetModifier("food", pepperFactor, speedEffectMoves)
+	case entities.FoodMushroom:
+		g.Speed.SetModifier("food", mushroomFactor, speedEffectMoves)
+	case entities.FoodClock:
+		g.State.TimeLeft += g.Mode.TimeBonus
+		g.Effects.FloatText(fmt.Sprintf("+%ds", int(g.Mode.TimeBonus.Seconds())), cx, cy-float64(g.cellSize))
+	case entities.FoodPoison:
+		g.die(causePoison, food.Pos)
+		g.Effects.Burst(cx, cy, 24, foodColors[food.Kind])
+		return
+	}
+	length := g.Snake.Length()
+	if g.Snake.Growing {
+		length++
+	}
+	points := 0
+	if spec.Points > 0 && g.Mode.TickScoreEvery == 0 {
+		points = g.Scoring.Bite(spec.Points, food.Age, length, g.PowerUps.Multiplier())
+	}
+	g.State.AddScore(points)
+	g.Speed.AdjustSpeedByLevel(g.State.Level)
+
+	// Eating an apple brings a new one and maybe a special item alongside it.
+	if food.Kind == entities.FoodApple {
+		g.spawnFood(entities.FoodApple)
+		if kind := entities.RandomFoodKind(g.rng, g.Mode.FoodWeights); kind != entities.FoodApple && !g.hasSpecialFood() {
+			g.spawnFood(kind)
+		}
+		if g.Mode.TimeBonusChance > 0 && !g.hasFood(entities.FoodClock) && g.rng.Intn(g.Mode.TimeBonusChance) == 0 {
+			g.spawnFood(entities.FoodClock)
+		}
+		if len(g.PowerUpItems) == 0 && g.rng.Intn(powerUpChance) == 0 {
+			if p, ok := entities.NewPowerUp(g.rng, g.gridWidth, g.gridHeight, g.cellBlocked); ok {
+				g.PowerUpItems = append(g.PowerUpItems, p)
+			}
+		}
+	}
+
+	g.Effects.Burst(cx, cy, 12, foodColors[food.Kind])
+	if points > 0 {
+		label := fmt.Sprintf("+%d", points)
+		if g.Scoring.Combo > 1 {
+			label = fmt.Sprintf("+%d x%d", points, g.Scoring.Combo)
+		}
+		g.Effects.FloatText(label, cx, cy-float64(g.cellSize))
+	}
+	if g.State.Level != level {
+		g.Effects.Sparkle(g.screenWidth, g.screenHeight)
+	}
+}
+
+// hasFood reports whether a food of the given kind is on the board.
+func (g *Game) hasFood(kind entities.FoodKind) bool {
+	for _, food := range g.Foods {
+		if food.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSpecialFood reports whether any rolled special food is on the board; apples
+// and clocks spawn on their own.
+func (g *Game) hasSpecialFood() bool {
+	for _, food := range g.Foods {
+		if food.Kind != entities.FoodApple && food.Kind != entities.FoodClock {
+			return true
+		}
+	}
+	return false
+}
+
+// applySkin activates the skin chosen in settings, falling back to the default
+// if it has not been unlocked yet.
+func (g *Game) applySkin() {
+	skin := render.SkinByName(g.Settings.Skin)
+	if !skin.Unlocked(g.Profile.BestScore) {
+		skin = render.Skins[0]
+	}
+	g.SpriteManager.SetSkin(skin)
+}
+
+// setDifficulty applies a difficulty preset to scoring and speed at the current level.
+func (g *Game) setDifficulty(d *Difficulty) {
+	g.Difficulty = d
+	g.State.Levels = d.Levels
+	g.Speed.Curve = d.Speed
+	g.Speed.AdjustSpeedByLevel(g.State.Level)
+}
+
+// setTheme switches the active theme, applies its palette to the UI and loads its music track.
+func (g *Game) setTheme(theme *ui.Theme) {
+	if theme == g.Theme {
+		return
+	}
+	g.Theme = theme
+	g.UI.TextColor = theme.Palette.Text
+	g.UI.AccentColor = theme.Palette.Accent
+	g.loadSounds()
 }
 
 // Helper to load all sounds
@@ -266,7 +749,7 @@ func (g *Game) loadSounds() {
 	}
 	g.SoundMan.ClearSounds()
 	// Load background music (looping)
-	bgData, err := os.ReadFile("game/audio/assets/sound_snake_movement_fixed.wav")
+	bgData, err := os.ReadFile(g.Theme.Music)
 	if err == nil {
 		if err := g.SoundMan.LoadLoopingSound("bgm", bgData); err != nil {
 			log.Printf("Failed to load background music: %v", err)
@@ -276,6 +759,15 @@ func (g *Game) loadSounds() {
 	} else {
 		log.Printf("Failed to read background music file: %v", err)
 	}
+	// Load crash sound
+	crashData, err := os.ReadFile("game/audio/assets/sound_collision.wav")
+	if err == nil {
+		if err := g.SoundMan.LoadSound("crash", crashData); err != nil {
+			log.Printf("Failed to load crash sound: %v", err)
+		}
+	} else {
+		log.Printf("Failed to read crash sound file: %v", err)
+	}
 	// Load apple bite sound
 	biteData, err := os.ReadFile("game/audio/assets/sound_apple_bite_fixed.wav")
 	if err == nil {
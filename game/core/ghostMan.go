@@ -0,0 +1,231 @@
+package core
+
+import (
+	"encoding/json"
+	"image/color"
+	"log"
+	"os"
+	"snakeGame/game/entities"
+	"time"
+)
+
+// ghostFile keeps the personal best run of every mode, difficulty and map.
+const ghostFile = "ghosts.json"
+
+// ghostVersion is bumped whenever a rules change would make recorded runs play out
+// differently; ghosts from other versions are replaced rather than raced.
+const ghostVersion = 3
+
+// ghostTint and ghostAlpha color and fade the ghost drawn over the board.
+var ghostTint = color.RGBA{0xb0, 0xd0, 0xff, 0xff}
+
+const ghostAlpha = 0.4
+
+// ghostFrame is the time step used when fast-forwarding a ghost, e.g. after a rewind.
+const ghostFrame = time.Second / 60
+
+// GhostRun is a recorded run: with its seed and inputs it plays out again exactly.
+type GhostRun struct {
+	Version    int          `json:"version"`
+	Seed       int64        `json:"seed"`
+	Map        string       `json:"map"` // map the run was played on; "Open" for an open board
+	GridWidth  int          `json:"grid_width"`
+	GridHeight int          `json:"grid_height"`
+	Score      int          `json:"score"`
+	Ticks      int          `json:"ticks"`
+	Crashed    bool         `json:"crashed"` // ended by a crash on the move after Ticks rather than by time
+	Inputs     []InputEvent `json:"inputs"`
+}
+
+// GhostStore holds the best run for each race, keyed by mode, difficulty and map.
+type GhostStore struct {
+	Runs map[string]*GhostRun `json:"runs"`
+	path string
+}
+
+// NewGhostStore loads the recorded runs, starting empty if there are none.
+func NewGhostStore() *GhostStore {
+	s := &GhostStore{path: ghostFile}
+	s.Load()
+	return s
+}
+
+// Load reads the recorded runs from disk. Missing or invalid files leave the store empty.
+func (s *GhostStore) Load() {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Printf("Failed to parse ghosts: %v", err)
+	}
+}
+
+// Save writes the recorded runs to disk.
+func (s *GhostStore) Save() {
+	data, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("Failed to encode ghosts: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		log.Printf("Failed to save ghosts: %v", err)
+	}
+}
+
+// Best returns the recorded run for key if it can be raced by this build.
+func (s *GhostStore) Best(key string) *GhostRun {
+	run := s.Runs[key]
+	if run == nil || run.Version != ghostVersion {
+		return nil
+	}
+	return run
+}
+
+// Record keeps run as the best for key if it scored higher than the one recorded,
+// and returns true if it did.
+func (s *GhostStore) Record(key string, run *GhostRun) bool {
+	if best := s.Best(key); best != nil && best.Score >= run.Score {
+		return false
+	}
+	if s.Runs == nil {
+		s.Runs = make(map[string]*GhostRun)
+	}
+	s.Runs[key] = run
+	s.Save()
+	return true
+}
+
+// Ghost replays a recorded run alongside the live one. It runs in a simulation of
+// its own, so it never touches or collides with the player's board.
+type Ghost struct {
+	Run      *GhostRun
+	sim      *Game
+	playback *InputPlayback
+}
+
+// newGhost starts the recorded run from its first move with the current rules.
+func (g *Game) newGhost(run *GhostRun) *Ghost {
+	// The ghost never rewinds, even when racing in practice mode.
+	mode := *g.Mode
+	mode.Rewind = false
+	sim := newHeadlessGame(run.GridWidth, run.GridHeight, &mode, g.Daily)
+	sim.Map = g.activeMap()
+	sim.setDifficulty(g.Difficulty)
+	sim.resetRun(run.Seed)
+	return &Ghost{Run: run, sim: sim, playback: NewInputPlayback(run.Inputs)}
+}
+
+// Advance moves the ghost on by dt of play time at the speed of the live rules.
+func (gh *Ghost) Advance(dt time.Duration) {
+	if gh.Done() {
+		return
+	}
+	gh.sim.State.Elapsed += dt
+	steps := gh.sim.Speed.Step(dt)
+	for i := 0; i < steps && !gh.Done(); i++ {
+		gh.playback.Apply(gh.sim.State.Ticks, gh.sim.Snake)
+		gh.sim.tick()
+	}
+}
+
+// Done reports whether the ghost has played out the recorded run: it stops after
+// the recorded number of moves, or crashes on the move after that.
+func (gh *Ghost) Done() bool {
+	last := gh.Run.Ticks
+	if gh.Run.Crashed {
+		last++
+	}
+	return gh.sim.State.GameOver || gh.sim.State.Ticks >= last
+}
+
+// Snake returns the ghost's snake.
+func (gh *Ghost) Snake() *entities.SnakeController {
+	return gh.sim.Snake
+}
+
+// Progress returns how far the ghost is towards its next move, from 0 to 1.
+func (gh *Ghost) Progress() float64 {
+	if gh.Done() {
+		return 0
+	}
+	return gh.sim.Speed.Progress()
+}
+
+// ghostKey names the race the current run belongs to, from the mode, difficulty and
+// map the run was started with. Daily challenges race the best attempt on the same day.
+func (g *Game) ghostKey() string {
+	if g.Daily != nil {
+		return "daily/" + g.Daily.Date
+	}
+	return g.Mode.Name + "/" + g.Difficulty.Name + "/" + g.runMapName()
+}
+
+// runMapName returns the name of the map the current run is played on.
+func (g *Game) runMapName() string {
+	if m := g.activeMap(); m != nil {
+		return m.Name
+	}
+	return "Open"
+}
+
+// raceRun returns the personal best to race in a new run, or nil when ghosts are
+// off or there is none for this board.
+func (g *Game) raceRun() *GhostRun {
+	if g.Ghosts == nil || g.testPlay || !g.Settings.Ghost {
+		return nil
+	}
+	run := g.Ghosts.Best(g.ghostKey())
+	if run == nil || run.Map != g.runMapName() {
+		return nil
+	}
+	if w, h := g.runGridSize(); run.GridWidth != w || run.GridHeight != h {
+		return nil
+	}
+	return run
+}
+
+// syncGhost sets up the ghost for the run in progress, caught up to the run's play
+// time. Only runs on the recorded run's seed get a ghost; with ghosts on, new runs
+// reuse that seed so the race is on the same board.
+func (g *Game) syncGhost() {
+	g.Ghost = nil
+	run := g.raceRun()
+	if run == nil || run.Seed != g.Seed {
+		return
+	}
+	g.Ghost = g.newGhost(run)
+	for t := time.Duration(0); t < g.State.Elapsed; t += ghostFrame {
+		g.Ghost.Advance(min(ghostFrame, g.State.Elapsed-t))
+	}
+}
+
+// recordGhost keeps the finished run as the one to race if it is a new best.
+func (g *Game) recordGhost() {
+	if g.Ghosts == nil {
+		return
+	}
+	run := &GhostRun{
+		Version:    ghostVersion,
+		Seed:       g.Seed,
+		Map:        g.runMapName(),
+		GridWidth:  g.gridWidth,
+		GridHeight: g.gridHeight,
+		Score:      g.State.Score,
+		Ticks:      g.State.Ticks,
+		Crashed:    g.State.GameOver,
+		Inputs:     append([]InputEvent(nil), g.Inputs.Events...),
+	}
+	if g.Ghosts.Record(g.ghostKey(), run) {
+		log.Printf("New ghost recorded for %s", g.ghostKey())
+	}
+}
+
+// drawGhost draws the ghost's snake under the live one. A ghost that crashed is
+// gone; one that ran out of time stays where it stopped.
+func (g *Game) drawGhost() {
+	if g.Ghost == nil || g.Ghost.sim.State.GameOver {
+		return
+	}
+	g.Renderer.DrawGhost(g.board, g.Ghost.Snake(), g.Ghost.Progress(), ghostTint, ghostAlpha)
+}
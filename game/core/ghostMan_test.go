@@ -0,0 +1,53 @@
+package core
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGhostKeyFollowsRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(g *Game) // settings changed from the pause menu mid-run
+	}{
+		{"nothing changed", func(g *Game) {}},
+		{"map changed", func(g *Game) { g.cycleMap(1) }},
+		{"difficulty changed", func(g *Game) { g.cycleDifficulty(1) }},
+		{"both changed", func(g *Game) { g.cycleMap(-1); g.cycleDifficulty(-1) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			box := &Map{Name: "Box"}
+			g := newHeadlessGame(20, 20, Modes[0], nil)
+			g.Maps = []*Map{box, {Name: "Other"}}
+			g.Difficulties = builtinDifficulties
+			g.Settings = &SettingsManager{Map: "Box", Difficulty: "Normal", Ghost: true, path: filepath.Join(dir, settingsFile)}
+			g.Ghosts = &GhostStore{path: filepath.Join(dir, ghostFile)}
+			g.Map = box
+			g.resetGame()
+			for i := 0; i < 5; i++ {
+				g.tick()
+			}
+
+			tt.change(g)
+			g.recordGhost()
+
+			key := Modes[0].Name + "/Normal/Box"
+			run := g.Ghosts.Best(key)
+			if run == nil {
+				t.Fatalf("no ghost recorded for %s, got %v", key, g.Ghosts.Runs)
+			}
+			if run.Map != "Box" {
+				t.Errorf("ghost map = %q, want Box", run.Map)
+			}
+			if g.raceRun() != run {
+				t.Error("the next run on the same board does not race the ghost")
+			}
+			g.Map = g.Maps[1]
+			if g.raceRun() != nil {
+				t.Error("a run on another map races the ghost")
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package core
+
+import (
+	"image"
+	"snakeGame/game/entities"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Hazard tuning shared by modes that use hazards.
+const (
+	hazardClearance = 3 // cells in front of the head kept free of new hazards
+	minBoardSize    = 8 // the board never shrinks below this many cells across
+)
+
+// HazardManager tracks the hazards that build up during a run: obstacles that
+// appear on free cells and walls that close the board in from the edges.
+type HazardManager struct {
+	Hazards       []*entities.Hazard
+	Inset         int // rows and columns walled off on every side
+	ShrinkWarning int // moves left before the next ring becomes solid; 0 = none pending
+	sinceHazard   int // moves since the last hazard appeared
+	sinceShrink   int // moves since the board last shrank
+}
+
+// NewHazardManager creates a manager with an empty, full-size board.
+func NewHazardManager() *HazardManager {
+	return &HazardManager{}
+}
+
+// Reset clears every hazard, used on restart.
+func (h *HazardManager) Reset() {
+	*h = HazardManager{}
+}
+
+// hazardAt returns the hazard at pos, or nil if there is none.
+func (g *Game) hazardAt(pos image.Point) *entities.Hazard {
+	for _, hz := range g.Hazards.Hazards {
+		if hz.Pos == pos {
+			return hz
+		}
+	}
+	return nil
+}
+
+// updateHazards advances hazards by one move for modes that use them: warnings
+// count down, new hazards appear and the board shrinks on the mode's schedule.
+func (g *Game) updateHazards() {
+	h := g.Hazards
+	for _, hz := range h.Hazards {
+		hz.Tick()
+	}
+
+	if g.Mode.HazardEvery > 0 {
+		h.sinceHazard++
+		if h.sinceHazard >= g.Mode.HazardEvery {
+			h.sinceHazard = 0
+			g.spawnHazard()
+		}
+	}
+
+	if g.Mode.ShrinkEvery > 0 {
+		if h.ShrinkWarning > 0 {
+			h.ShrinkWarning--
+			if h.ShrinkWarning == 0 {
+				g.shrinkBoard()
+			}
+		}
+		h.sinceShrink++
+		if h.sinceShrink >= g.Mode.ShrinkEvery && h.ShrinkWarning == 0 && g.canShrink() {
+			h.sinceShrink = 0
+			h.ShrinkWarning = g.Mode.HazardWarning
+		}
+	}
+}
+
+// spawnHazard places a new hazard on a free cell away from the snake's path.
+func (g *Game) spawnHazard() {
+	w, h := g.playWidth(), g.playHeight()
+	if len(g.Hazards.Hazards) >= w*h/5 {
+		return
+	}
+	if hz := entities.NewHazard(g.rng, g.gridWidth, g.gridHeight, g.Mode.HazardWarning, g.hazardBlocked); hz != nil {
+		g.Hazards.Hazards = append(g.Hazards.Hazards, hz)
+	}
+}
+
+// hazardBlocked reports whether a hazard may not appear at pos: on anything already
+// on the board, or on or just in front of the snake's head.
+func (g *Game) hazardBlocked(pos image.Point) bool {
+	if g.cellBlocked(pos) {
+		return true
+	}
+	head := g.Snake.HeadPos()
+	dir := g.Snake.UpcomingDir()
+	for i := 1; i <= hazardClearance; i++ {
+		if pos == head.Add(dir.Mul(i)) {
+			return true
+		}
+	}
+	d := pos.Sub(head)
+	return abs(d.X) <= 1 && abs(d.Y) <= 1
+}
+
+// placeRocks puts n solid hazards on the board at the start of a run, keeping
+// clear of the snake and its path.
+func (g *Game) placeRocks(n int) {
+	for i := 0; i < n; i++ {
+		hz := entities.NewHazard(g.rng, g.gridWidth, g.gridHeight, 0, g.hazardBlocked)
+		if hz == nil {
+			return
+		}
+		g.Hazards.Hazards = append(g.Hazards.Hazards, hz)
+	}
+}
+
+// canShrink reports whether another ring can be walled off without going below
+// the minimum board size.
+func (g *Game) canShrink() bool {
+	return g.playWidth()-2 >= minBoardSize && g.playHeight()-2 >= minBoardSize
+}
+
+// shrinkBoard walls off the outermost ring of the playable area. Items caught in
+// the ring are lost; apples are replaced so one stays reachable. The snake loses
+// everything from its first segment in the ring back to its tail, and crashes if
+// that leaves it shorter than a berry could make it.
+func (g *Game) shrinkBoard() {
+	g.Hazards.Inset++
+	keptFood := g.Foods[:0]
+	lostApple := false
+	for _, food := range g.Foods {
+		if g.outOfBounds(food.Pos) {
+			lostApple = lostApple || food.Kind == entities.FoodApple
+			continue
+		}
+		keptFood = append(keptFood, food)
+	}
+	g.Foods = keptFood
+	if lostApple {
+		g.spawnFood(entities.FoodApple)
+	}
+
+	keptItems := g.PowerUpItems[:0]
+	for _, item := range g.PowerUpItems {
+		if !g.outOfBounds(item.Pos) {
+			keptItems = append(keptItems, item)
+		}
+	}
+	g.PowerUpItems = keptItems
+
+	keptHazards := g.Hazards.Hazards[:0]
+	for _, hz := range g.Hazards.Hazards {
+		if !g.outOfBounds(hz.Pos) {
+			keptHazards = append(keptHazards, hz)
+		}
+	}
+	g.Hazards.Hazards = keptHazards
+
+	keptMovers := g.Movers[:0]
+	for _, m := range g.Movers {
+		if !g.outOfBounds(m.Pos) {
+			keptMovers = append(keptMovers, m)
+		}
+	}
+	g.Movers = keptMovers
+
+	// A portal stops working once either end falls off the board.
+	keptPortals := g.Portals[:0]
+	for _, p := range g.Portals {
+		if !g.outOfBounds(p.A) && !g.outOfBounds(p.B) {
+			keptPortals = append(keptPortals, p)
+		}
+	}
+	g.Portals = keptPortals
+
+	kept := 0
+	for seg := g.Snake.Head; seg != nil; seg = seg.Next {
+		if g.outOfBounds(seg.Pos) {
+			if kept < minSnakeLength {
+				g.die(causeWall, seg.Pos)
+				return
+			}
+			g.Snake.Shrink(g.Snake.Length()-kept, minSnakeLength)
+			break
+		}
+		kept++
+	}
+	g.Effects.Shake(10, 2)
+}
+
+// playWidth and playHeight are the size of the area inside any shrunk walls.
+func (g *Game) playWidth() int  { return g.gridWidth - 2*g.Hazards.Inset }
+func (g *Game) playHeight() int { return g.gridHeight - 2*g.Hazards.Inset }
+
+// drawHazards draws hazards and shrunk walls onto the board. Anything still
+// warning blinks.
+func (g *Game) drawHazards(board *ebiten.Image) {
+	blink := (g.State.Ticks/2)%2 == 0
+	for _, hz := range g.Hazards.Hazards {
+		if hz.Solid() {
+			g.Theme.DrawHazard(board, hz.Pos, g.cellSize, 1)
+		} else if blink {
+			g.Theme.DrawHazard(board, hz.Pos, g.cellSize, 0.5)
+		}
+	}
+
+	inset := g.Hazards.Inset
+	if inset == 0 && g.Hazards.ShrinkWarning == 0 {
+		return
+	}
+	warn := g.Hazards.ShrinkWarning > 0
+	for y := 0; y < g.gridHeight; y++ {
+		for x := 0; x < g.gridWidth; x++ {
+			ring := min(x, y, g.gridWidth-1-x, g.gridHeight-1-y)
+			switch {
+			case ring < inset:
+				g.Theme.DrawHazard(board, image.Pt(x, y), g.cellSize, 1)
+			case ring == inset && warn && blink:
+				g.Theme.DrawHazard(board, image.Pt(x, y), g.cellSize, 0.5)
+			}
+		}
+	}
+}
@@ -20,30 +20,29 @@ func (g *Game) handleInput() {
 	}
 }
 
-func (g *Game) handlePauseToggle() {
-	if inpututil.IsKeyJustPressed(ebiten.KeyEnter) {
-		g.State.TogglePause()
+// handlePauseToggle opens the pause menu when a pause key is pressed or the window
+// loses focus, and reports whether it did. A run that is dying or out of time has
+// nothing left to pause.
+func (g *Game) handlePauseToggle() bool {
+	if g.State.GameOver || g.State.TimeUp {
+		return false
 	}
+	if !g.pausePressed() && !inpututil.IsKeyJustPressed(ebiten.KeyEnter) && ebiten.IsFocused() {
+		return false
+	}
+	g.Screens.Push(&pausedScreen{g: g}, TransitionNone)
+	return true
 }
 
-func (g *Game) handleRetryInput() {
-	// Keyboard restart
-	if inpututil.IsKeyJustPressed(ebiten.KeyP) {
-		g.resetGame()
-		return
+// pausePressed reports whether Escape, P or a gamepad's Start button was pressed this frame.
+func (g *Game) pausePressed() bool {
+	if inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyP) {
+		return true
 	}
-
-	// Mouse click restart
-	mx, my := ebiten.CursorPosition()
-	centerX := g.screenWidth / 2
-	centerY := g.screenHeight / 2
-	x := centerX - 80
-	y := centerY
-	w, h := 160, 30
-
-	if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
-		if mx >= x && mx <= x+w && my >= y && my <= y+h {
-			g.resetGame()
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if inpututil.IsStandardGamepadButtonJustPressed(id, ebiten.StandardGamepadButtonCenterRight) {
+			return true
 		}
 	}
+	return false
 }
@@ -0,0 +1,489 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"log"
+	"os"
+	"path/filepath"
+	"snakeGame/game/entities"
+	"sort"
+	"strings"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// mapsDir holds the board layouts selectable in settings.
+const mapsDir = "game/maps"
+
+// Grid sizes a map may ask for, in cells.
+const (
+	minMapSize = 10
+	maxMapSize = 40
+)
+
+// Map is a board layout: solid walls, linked portals, where the snake starts and
+// food spawns, and enemies that move on their own.
+type Map struct {
+	Name      string      `json:"name"`
+	Author    string      `json:"author,omitempty"`
+	Width     int         `json:"width,omitempty"`  // grid size; 0 keeps the window's default
+	Height    int         `json:"height,omitempty"` // grid size; 0 keeps the window's default
+	Spawn     *spawnDef   `json:"spawn,omitempty"`  // snake start; nil starts in the middle facing right
+	Walls     []cell      `json:"walls,omitempty"`
+	Portals   []portalDef `json:"portals,omitempty"`
+	FoodZones []cell      `json:"food_zones,omitempty"` // cells food spawns on; empty allows any cell
+	Movers    []moverDef  `json:"movers,omitempty"`
+
+	path string // file the map was loaded from or saved to
+}
+
+// spawnDef places the snake's head at the start of a run.
+type spawnDef struct {
+	Pos cell `json:"pos"`
+	Dir cell `json:"dir"` // defaults to right
+}
+
+// dir returns the direction the snake starts in.
+func (s *spawnDef) dir() image.Point {
+	if s.Dir == (cell{}) {
+		return entities.Right
+	}
+	return s.Dir.point()
+}
+
+// portalDef links two cells of a map.
+type portalDef struct {
+	A cell `json:"a"`
+	B cell `json:"b"`
+}
+
+// cell is a grid position written as [x, y] in map files.
+type cell [2]int
+
+func (c cell) point() image.Point { return image.Pt(c[0], c[1]) }
+
+// toCell converts a grid position for storing in a map.
+func toCell(p image.Point) cell { return cell{p.X, p.Y} }
+
+// moverDef describes an enemy in a map file.
+type moverDef struct {
+	Kind  string `json:"kind"`  // "patrol" or "bouncer"
+	Path  []cell `json:"path"`  // patrol waypoints
+	Loop  bool   `json:"loop"`  // patrols loop back to the start instead of reversing
+	Pos   cell   `json:"pos"`   // bouncer start
+	Dir   cell   `json:"dir"`   // bouncer direction
+	Every int    `json:"every"` // snake moves per step; defaults to 1
+}
+
+// loadMaps loads every *.json map in dir, sorted by name. Maps that fail to load
+// are logged and skipped.
+func loadMaps(dir string) []*Map {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		log.Printf("Failed to list maps: %v", err)
+		return nil
+	}
+	var maps []*Map
+	for _, path := range paths {
+		m, err := loadMap(path)
+		if err != nil {
+			log.Printf("Failed to load map %s: %v", path, err)
+			continue
+		}
+		maps = append(maps, m)
+	}
+	sort.Slice(maps, func(i, j int) bool { return maps[i].Name < maps[j].Name })
+	return maps
+}
+
+// loadMap reads and checks a single map file.
+func loadMap(path string) (*Map, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m Map
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	m.path = path
+	for _, size := range []int{m.Width, m.Height} {
+		if size != 0 && (size < minMapSize || size > maxMapSize) {
+			return nil, fmt.Errorf("grid size %dx%d outside %d..%d", m.Width, m.Height, minMapSize, maxMapSize)
+		}
+	}
+	if m.Spawn != nil && !validDir(m.Spawn.dir()) {
+		return nil, fmt.Errorf("spawn direction %v is not up, down, left or right", m.Spawn.Dir)
+	}
+	for _, p := range m.Portals {
+		if p.A == p.B {
+			return nil, fmt.Errorf("portal linked to itself at %v", p.A)
+		}
+	}
+	for _, d := range m.Movers {
+		switch d.Kind {
+		case "patrol":
+			if len(d.Path) == 0 {
+				return nil, fmt.Errorf("patrol without a path")
+			}
+		case "bouncer":
+			if !validBouncerDir(d.Dir.point()) {
+				return nil, fmt.Errorf("bouncer direction %v is not a step to a neighboring cell", d.Dir)
+			}
+		default:
+			return nil, fmt.Errorf("unknown mover kind %q", d.Kind)
+		}
+	}
+	return &m, nil
+}
+
+// mapByName returns the map with the given name, or nil for an open board.
+func mapByName(maps []*Map, name string) *Map {
+	for _, m := range maps {
+		if m.Name == name {
+			return m
+		}
+	}
+	return nil
+}
+
+// validDir reports whether d is one of the four directions the snake can move in.
+func validDir(d image.Point) bool {
+	return d == entities.Up || d == entities.Down || d == entities.Left || d == entities.Right
+}
+
+// validBouncerDir reports whether d steps to one of the eight neighboring cells.
+func validBouncerDir(d image.Point) bool {
+	return d != image.Point{} && abs(d.X) <= 1 && abs(d.Y) <= 1
+}
+
+// spawnFits reports whether a snake starting at pos facing dir lies entirely on a
+// board of w×h cells. Its tail starts three cells behind the head.
+func spawnFits(pos, dir image.Point, w, h int) bool {
+	board := image.Rect(0, 0, w, h)
+	return pos.In(board) && pos.Sub(dir.Mul(3)).In(board)
+}
+
+// start returns where the snake starts on a w×h board: the map's spawn when the
+// whole snake fits there, otherwise the middle facing right. A nil map is an
+// open board.
+func (m *Map) start(w, h int) (pos, dir image.Point) {
+	if m != nil && m.Spawn != nil {
+		pos, dir = m.Spawn.Pos.point(), m.Spawn.dir()
+		if spawnFits(pos, dir, w, h) {
+			return pos, dir
+		}
+	}
+	return image.Pt(w/2, h/2), entities.Right
+}
+
+// encode lays a map out like the hand-written files: one field per line, with
+// cells wrapped in rows and one portal or enemy per line.
+func (m *Map) encode() ([]byte, error) {
+	type field struct {
+		key    string
+		value  any   // written on one line
+		list   bool  // value is written from items instead
+		items  []any // list entries, perRow to a line
+		perRow int
+	}
+	fields := []field{{key: "name", value: m.Name}}
+	if m.Author != "" {
+		fields = append(fields, field{key: "author", value: m.Author})
+	}
+	if m.Width != 0 || m.Height != 0 {
+		fields = append(fields, field{key: "width", value: m.Width}, field{key: "height", value: m.Height})
+	}
+	if m.Spawn != nil {
+		fields = append(fields, field{key: "spawn", value: m.Spawn})
+	}
+	fields = append(fields,
+		field{key: "walls", list: true, items: anySlice(m.Walls), perRow: 8},
+		field{key: "portals", list: true, items: anySlice(m.Portals), perRow: 1},
+		field{key: "food_zones", list: true, items: anySlice(m.FoodZones), perRow: 8},
+		field{key: "movers", list: true, items: anySlice(m.Movers), perRow: 1},
+	)
+
+	var lines []string
+	for _, f := range fields {
+		if !f.list {
+			data, err := json.Marshal(f.value)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, fmt.Sprintf("  %q: %s", f.key, data))
+			continue
+		}
+		if len(f.items) == 0 {
+			continue
+		}
+		var rows []string
+		for i := 0; i < len(f.items); i += f.perRow {
+			data, err := json.Marshal(f.items[i:min(i+f.perRow, len(f.items))])
+			if err != nil {
+				return nil, err
+			}
+			rows = append(rows, "    "+string(data[1:len(data)-1]))
+		}
+		lines = append(lines, fmt.Sprintf("  %q: [\n%s\n  ]", f.key, strings.Join(rows, ",\n")))
+	}
+	return []byte("{\n" + strings.Join(lines, ",\n") + "\n}\n"), nil
+}
+
+// anySlice converts a list for encoding alongside lists of other types.
+func anySlice[T any](items []T) []any {
+	out := make([]any, len(items))
+	for i, item := range items {
+		out[i] = item
+	}
+	return out
+}
+
+// MarshalJSON writes only the fields that apply to the enemy's kind.
+func (d moverDef) MarshalJSON() ([]byte, error) {
+	if d.Kind == "patrol" {
+		return json.Marshal(struct {
+			Kind  string `json:"kind"`
+			Path  []cell `json:"path"`
+			Loop  bool   `json:"loop,omitempty"`
+			Every int    `json:"every,omitempty"`
+		}{d.Kind, d.Path, d.Loop, d.Every})
+	}
+	return json.Marshal(struct {
+		Kind  string `json:"kind"`
+		Pos   cell   `json:"pos"`
+		Dir   cell   `json:"dir"`
+		Every int    `json:"every,omitempty"`
+	}{d.Kind, d.Pos, d.Dir, d.Every})
+}
+
+// startCell returns where the enemy starts.
+func (d moverDef) startCell() cell {
+	if d.Kind == "patrol" && len(d.Path) > 0 {
+		return d.Path[0]
+	}
+	return d.Pos
+}
+
+// newMover creates the enemy a map entry describes.
+func (d moverDef) newMover() *entities.Mover {
+	every := d.Every
+	if every < 1 {
+		every = 1
+	}
+	if d.Kind == "patrol" {
+		path := make([]image.Point, len(d.Path))
+		for i, c := range d.Path {
+			path[i] = c.point()
+		}
+		return entities.NewPatrol(path, d.Loop, every)
+	}
+	return entities.NewBouncer(d.Pos.point(), d.Dir.point(), every)
+}
+
+// activeMap returns the map for the current run. Daily challenges always use an
+// open board so that their results do not depend on installed maps.
+func (g *Game) activeMap() *Map {
+	if g.Daily != nil {
+		return nil
+	}
+	return g.Map
+}
+
+// runGridSize returns the board size for a run on the active map.
+func (g *Game) runGridSize() (int, int) {
+	w, h := g.defaultGrid.X, g.defaultGrid.Y
+	if m := g.activeMap(); m != nil {
+		if m.Width > 0 {
+			w = m.Width
+		}
+		if m.Height > 0 {
+			h = m.Height
+		}
+	}
+	return w, h
+}
+
+// applyMap places the active map's walls, portals, food zones and enemies for a
+// new run. Anything outside the board or on the snake's starting cells is ignored.
+func (g *Game) applyMap() {
+	g.Movers = nil
+	g.Portals = nil
+	g.foodZone = nil
+	m := g.activeMap()
+	if m == nil {
+		return
+	}
+	for _, c := range m.Walls {
+		if p := c.point(); !g.outOfBounds(p) && !g.Snake.Occupies(p) {
+			g.Hazards.Hazards = append(g.Hazards.Hazards, &entities.Hazard{Pos: p})
+		}
+	}
+	for _, d := range m.Portals {
+		p := entities.Portal{A: d.A.point(), B: d.B.point()}
+		if g.outOfBounds(p.A) || g.outOfBounds(p.B) || g.Snake.Occupies(p.A) || g.Snake.Occupies(p.B) {
+			continue
+		}
+		g.Portals = append(g.Portals, p)
+	}
+	for _, c := range m.FoodZones {
+		if p := c.point(); !g.outOfBounds(p) {
+			if g.foodZone == nil {
+				g.foodZone = make(map[image.Point]bool)
+			}
+			g.foodZone[p] = true
+		}
+	}
+	for _, d := range m.Movers {
+		if mover := d.newMover(); !g.outOfBounds(mover.Pos) && !g.Snake.Occupies(mover.Pos) {
+			g.Movers = append(g.Movers, mover)
+		}
+	}
+}
+
+// foodZoneOpen reports whether food should keep to the map's food zones, which
+// holds while any zone cell is free.
+func (g *Game) foodZoneOpen() bool {
+	for pos := range g.foodZone {
+		if !g.cellBlocked(pos) {
+			return true
+		}
+	}
+	return false
+}
+
+// moverAt returns the enemy at pos, or nil if there is none.
+func (g *Game) moverAt(pos image.Point) *entities.Mover {
+	for _, m := range g.Movers {
+		if m.Pos == pos {
+			return m
+		}
+	}
+	return nil
+}
+
+// moverBlocked reports cells an enemy may not step into. The snake's head is open
+// so that enemies can run into it.
+func (g *Game) moverBlocked(pos image.Point) bool {
+	if g.outOfBounds(pos) || g.moverAt(pos) != nil || g.portalAt(pos) {
+		return true
+	}
+	if hz := g.hazardAt(pos); hz != nil && hz.Solid() {
+		return true
+	}
+	return pos != g.Snake.HeadPos() && g.Snake.Occupies(pos)
+}
+
+// updateMovers steps every enemy and resolves any that reach the snake's head.
+// The rest stay put once one of them has killed the snake.
+func (g *Game) updateMovers() {
+	// Iterate over a copy, since smashing an enemy removes it from the list.
+	for _, m := range append([]*entities.Mover(nil), g.Movers...) {
+		m.Tick(g.moverBlocked)
+		if m.Pos == g.Snake.HeadPos() && g.hitMover(m) {
+			return
+		}
+	}
+}
+
+// hitMover resolves the snake touching an enemy. While ghost is active the snake
+// passes through it unharmed; otherwise a shield charge smashes it, and without
+// one the snake dies. Magnet, score and slow-time do nothing to enemies, though
+// slow-time slows them too since they step with the snake. It reports whether the
+// snake died.
+func (g *Game) hitMover(m *entities.Mover) bool {
+	if g.PowerUps.Active(entities.PowerGhost) {
+		return false
+	}
+	if !g.PowerUps.UseShield() {
+		g.die(causeObstacle, m.Pos)
+		return true
+	}
+	for i, other := range g.Movers {
+		if other == m {
+			g.Movers = append(g.Movers[:i], g.Movers[i+1:]...)
+			break
+		}
+	}
+	cx := float64(m.Pos.X*g.cellSize + g.cellSize/2)
+	cy := float64(m.Pos.Y*g.cellSize + g.cellSize/2 + HUDHeight(g.cellSize))
+	g.Effects.Burst(cx, cy, 20, g.Theme.Palette.Accent)
+	g.Effects.Shake(8, 2)
+	g.SoundMan.PlaySound("crash")
+	return false
+}
+
+// portalExit returns where a head entering pos comes out, or false if pos is not a portal.
+func (g *Game) portalExit(pos image.Point) (image.Point, bool) {
+	for _, p := range g.Portals {
+		if exit, ok := p.Exit(pos); ok {
+			return exit, true
+		}
+	}
+	return image.Point{}, false
+}
+
+// portalAt reports whether pos is an end of any portal.
+func (g *Game) portalAt(pos image.Point) bool {
+	_, ok := g.portalExit(pos)
+	return ok
+}
+
+// portalColors tell portal pairs apart; pairs beyond the list reuse its colors.
+var portalColors = []color.RGBA{
+	{R: 0x4f, G: 0xc3, B: 0xf7, A: 0xff},
+	{R: 0xff, G: 0x8a, B: 0x65, A: 0xff},
+	{R: 0xba, G: 0x68, B: 0xc8, A: 0xff},
+	{R: 0x81, G: 0xc7, B: 0x84, A: 0xff},
+}
+
+// drawPortals draws both ends of every portal, each pair in its own color.
+func (g *Game) drawPortals(board *ebiten.Image) {
+	for i, p := range g.Portals {
+		c := portalColors[i%len(portalColors)]
+		g.Theme.DrawPortal(board, p.A, g.cellSize, c)
+		g.Theme.DrawPortal(board, p.B, g.cellSize, c)
+	}
+}
+
+// drawMovers draws every enemy onto the board.
+func (g *Game) drawMovers(board *ebiten.Image) {
+	for _, m := range g.Movers {
+		g.Theme.DrawMover(board, moverSprites[m.Kind], m.Pos, g.cellSize)
+	}
+}
+
+// cycleMap steps through the installed maps, with an open board first, and saves
+// the choice. The new map applies from the next run.
+func (g *Game) cycleMap(step int) {
+	current := 0
+	for i, m := range g.Maps {
+		if m.Name == g.Settings.Map {
+			current = i + 1
+		}
+	}
+	n := len(g.Maps) + 1
+	next := (current + step + n) % n
+	g.Settings.Map = ""
+	if next > 0 {
+		g.Settings.Map = g.Maps[next-1].Name
+	}
+	g.Settings.Save()
+}
+
+// mapName returns the name of the map chosen in settings for display.
+func (g *Game) mapName() string {
+	if m := mapByName(g.Maps, g.Settings.Map); m != nil {
+		return m.Name
+	}
+	return "Open"
+}
+
+// moverSprites names the theme sprite for each kind of enemy.
+var moverSprites = map[entities.MoverKind]string{
+	entities.MoverPatrol:  "patrol",
+	entities.MoverBouncer: "bouncer",
+}
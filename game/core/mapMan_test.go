@@ -0,0 +1,257 @@
+package core
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"reflect"
+	"snakeGame/game/entities"
+	"strings"
+	"testing"
+)
+
+func TestMapRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		m    *Map
+	}{
+		{"name only", &Map{Name: "Empty"}},
+		{"sized", &Map{Name: "Sized", Author: "tester", Width: 24, Height: 18}},
+		{"spawn", &Map{Name: "Spawn", Spawn: &spawnDef{Pos: cell{4, 9}, Dir: cell{0, -1}}}},
+		{"walls in rows", &Map{Name: "Walls", Walls: []cell{
+			{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}, {5, 0}, {6, 0}, {7, 0}, {8, 0}, {9, 0},
+		}}},
+		{"portals and food zones", &Map{
+			Name:      "Warp",
+			Portals:   []portalDef{{A: cell{1, 1}, B: cell{10, 10}}, {A: cell{2, 5}, B: cell{5, 2}}},
+			FoodZones: []cell{{3, 3}, {3, 4}, {4, 3}},
+		}},
+		{"movers", &Map{Name: "Movers", Movers: []moverDef{
+			{Kind: "patrol", Path: []cell{{3, 3}, {16, 3}, {16, 16}}, Loop: true, Every: 2},
+			{Kind: "patrol", Path: []cell{{7, 7}}},
+			{Kind: "bouncer", Pos: cell{5, 5}, Dir: cell{1, -1}, Every: 3},
+			{Kind: "bouncer", Pos: cell{8, 2}, Dir: cell{0, 1}},
+		}}},
+		{"quotes in the name", &Map{Name: `The "Box"`, Author: "a\\b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := roundTrip(t, tt.m)
+			if !reflect.DeepEqual(got, tt.m) {
+				t.Errorf("loaded %+v, want %+v", got, tt.m)
+			}
+		})
+	}
+}
+
+func TestBundledMapsRoundTrip(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "maps", "*.json"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no bundled maps found: %v", err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			m, err := loadMap(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			m.path = ""
+			got := roundTrip(t, m)
+			if !reflect.DeepEqual(got, m) {
+				t.Fatalf("loaded %+v, want %+v", got, m)
+			}
+
+			// A run on the saved copy, on the default 20x20 board, starts where the
+			// original map says.
+			g := newHeadlessGame(20, 20, Modes[0], nil)
+			g.Map = got
+			g.resetGame()
+			w, h := g.runGridSize()
+			if g.gridWidth != w || g.gridHeight != h {
+				t.Errorf("board %dx%d, want %dx%d", g.gridWidth, g.gridHeight, w, h)
+			}
+			if pos, _ := m.start(w, h); g.Snake.HeadPos() != pos {
+				t.Errorf("snake starts at %v, want %v", g.Snake.HeadPos(), pos)
+			}
+			if len(g.Movers) != len(m.Movers) {
+				t.Errorf("%d enemies on the board, want %d", len(g.Movers), len(m.Movers))
+			}
+		})
+	}
+}
+
+func TestLoadMapRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr string // "" when the map should load
+	}{
+		{"too small", `{"name": "x", "width": 2, "height": 20}`, "grid size"},
+		{"diagonal spawn", `{"name": "x", "spawn": {"pos": [3, 3], "dir": [1, 1]}}`, "spawn direction"},
+		{"portal to itself", `{"name": "x", "portals": [{"a": [2, 2], "b": [2, 2]}]}`, "portal"},
+		{"patrol without path", `{"name": "x", "movers": [{"kind": "patrol"}]}`, "patrol"},
+		{"bouncer standing still", `{"name": "x", "movers": [{"kind": "bouncer", "pos": [1, 1]}]}`, "bouncer direction"},
+		{"bouncer jumping", `{"name": "x", "movers": [{"kind": "bouncer", "pos": [1, 1], "dir": [2, 0]}]}`, "bouncer direction"},
+		{"bouncer jumping back", `{"name": "x", "movers": [{"kind": "bouncer", "pos": [1, 1], "dir": [1, -2]}]}`, "bouncer direction"},
+		{"unknown enemy", `{"name": "x", "movers": [{"kind": "ghost"}]}`, "unknown mover"},
+		{"diagonal bouncer", `{"name": "x", "movers": [{"kind": "bouncer", "pos": [1, 1], "dir": [-1, 1]}]}`, ""},
+		{"straight bouncer", `{"name": "x", "movers": [{"kind": "bouncer", "pos": [1, 1], "dir": [0, -1]}]}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "map.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadMap(path)
+			switch {
+			case tt.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tt.wantErr != "" && err == nil:
+				t.Fatalf("loaded, want an error containing %q", tt.wantErr)
+			case tt.wantErr != "" && !strings.Contains(err.Error(), tt.wantErr):
+				t.Fatalf("error %q, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// roundTrip encodes m to a file and loads it back, without the path it was loaded from.
+func roundTrip(t *testing.T, m *Map) *Map {
+	t.Helper()
+	data, err := m.encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "map.json")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := loadMap(path)
+	if err != nil {
+		t.Fatalf("%v\n%s", err, data)
+	}
+	got.path = ""
+	return got
+}
+
+func TestHitMover(t *testing.T) {
+	tests := []struct {
+		name     string
+		powerUps []entities.PowerUpKind
+		died     bool
+		smashed  bool
+	}{
+		{"no power-up", nil, true, false},
+		{"shield", []entities.PowerUpKind{entities.PowerShield}, false, true},
+		{"ghost", []entities.PowerUpKind{entities.PowerGhost}, false, false},
+		{"ghost keeps the shield", []entities.PowerUpKind{entities.PowerGhost, entities.PowerShield}, false, false},
+		{"magnet", []entities.PowerUpKind{entities.PowerMagnet}, true, false},
+		{"score", []entities.PowerUpKind{entities.PowerMultiplier}, true, false},
+		{"slow time", []entities.PowerUpKind{entities.PowerSlowTime}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newHeadlessGame(20, 20, Modes[0], nil)
+			g.resetGame()
+			m := entities.NewBouncer(g.Snake.NextHeadPosition(), image.Pt(-1, 0), 1)
+			g.Movers = []*entities.Mover{m}
+			for _, kind := range tt.powerUps {
+				g.PowerUps.Activate(kind)
+			}
+			shielded := g.PowerUps.Active(entities.PowerShield)
+
+			if died := g.hitMover(m); died != tt.died || g.State.GameOver != tt.died {
+				t.Errorf("died = %v (game over %v), want %v", died, g.State.GameOver, tt.died)
+			}
+			if smashed := len(g.Movers) == 0; smashed != tt.smashed {
+				t.Errorf("smashed = %v, want %v", smashed, tt.smashed)
+			}
+			if shielded && !tt.smashed && !g.PowerUps.Active(entities.PowerShield) {
+				t.Error("shield charge spent without smashing the enemy")
+			}
+		})
+	}
+}
+
+func TestPortals(t *testing.T) {
+	pt := image.Pt
+	tests := []struct {
+		name    string
+		portals []entities.Portal
+		walls   []image.Point
+		food    []image.Point
+		moves   int
+		head    image.Point
+		cause   deathCause
+		ate     bool
+	}{
+		{"into one end", []entities.Portal{{A: pt(11, 10), B: pt(3, 4)}}, nil, nil, 1, pt(3, 4), causeNone, false},
+		{"into the other end", []entities.Portal{{A: pt(3, 4), B: pt(11, 10)}}, nil, nil, 1, pt(3, 4), causeNone, false},
+		{"keeps its direction", []entities.Portal{{A: pt(11, 10), B: pt(3, 4)}}, nil, nil, 3, pt(5, 4), causeNone, false},
+		{"passing by", []entities.Portal{{A: pt(11, 11), B: pt(3, 4)}}, nil, nil, 1, pt(11, 10), causeNone, false},
+		{"second pair", []entities.Portal{{A: pt(11, 11), B: pt(3, 4)}, {A: pt(15, 2), B: pt(11, 10)}}, nil, nil, 1, pt(15, 2), causeNone, false},
+		{"at the edge of the board", []entities.Portal{{A: pt(19, 10), B: pt(0, 5)}}, nil, nil, 10, pt(1, 5), causeNone, false},
+		{"food at the exit", []entities.Portal{{A: pt(11, 10), B: pt(3, 4)}}, nil, []image.Point{pt(3, 4)}, 1, pt(3, 4), causeNone, true},
+		{"wall at the exit", []entities.Portal{{A: pt(11, 10), B: pt(3, 4)}}, []image.Point{pt(3, 4)}, nil, 1, pt(10, 10), causeObstacle, false},
+		{"own body at the exit", []entities.Portal{{A: pt(11, 10), B: pt(8, 10)}}, nil, nil, 1, pt(10, 10), causeSelf, false},
+		{"tail moving off the exit", []entities.Portal{{A: pt(11, 10), B: pt(7, 10)}}, nil, nil, 1, pt(7, 10), causeNone, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newHeadlessGame(20, 20, Modes[0], nil)
+			g.resetGame()
+			g.Portals = tt.portals
+			g.Hazards.Hazards = nil
+			for _, p := range tt.walls {
+				g.Hazards.Hazards = append(g.Hazards.Hazards, &entities.Hazard{Pos: p})
+			}
+			g.Foods = nil
+			for _, p := range tt.food {
+				g.Foods = append(g.Foods, &entities.Food{Pos: p, Kind: entities.FoodApple})
+			}
+			dir := g.Snake.Dir
+
+			for range tt.moves {
+				g.tick()
+			}
+			if g.State.GameOver != (tt.cause != causeNone) || g.deathCause != tt.cause {
+				t.Errorf("game over = %v by %v, want cause %v", g.State.GameOver, g.deathCause, tt.cause)
+			}
+			if head := g.Snake.Head.Pos; head != tt.head {
+				t.Errorf("head at %v, want %v", head, tt.head)
+			}
+			if g.Snake.Dir != dir {
+				t.Errorf("heading %v, want %v", g.Snake.Dir, dir)
+			}
+			if ate := g.State.Score > 0; ate != tt.ate {
+				t.Errorf("ate = %v, want %v", ate, tt.ate)
+			}
+		})
+	}
+}
+
+func TestApplyMapPortals(t *testing.T) {
+	tests := []struct {
+		name    string
+		portals []portalDef
+		want    []entities.Portal
+	}{
+		{"both ends on the board", []portalDef{{A: cell{2, 2}, B: cell{17, 17}}},
+			[]entities.Portal{{A: image.Pt(2, 2), B: image.Pt(17, 17)}}},
+		{"one end off the board", []portalDef{{A: cell{2, 2}, B: cell{20, 5}}}, nil},
+		{"one end under the snake", []portalDef{{A: cell{2, 2}, B: cell{9, 10}}}, nil},
+		{"only the good pair kept", []portalDef{{A: cell{-1, 0}, B: cell{5, 5}}, {A: cell{4, 4}, B: cell{15, 4}}},
+			[]entities.Portal{{A: image.Pt(4, 4), B: image.Pt(15, 4)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newHeadlessGame(20, 20, Modes[0], nil)
+			g.Map = &Map{Name: "Warp", Portals: tt.portals}
+			g.resetGame()
+			if !reflect.DeepEqual(g.Portals, tt.want) {
+				t.Errorf("portals = %v, want %v", g.Portals, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,369 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"snakeGame/game/render"
+	"strconv"
+)
+
+// buildMenus creates the title, pause, settings, high score, game-over and editor menus. Item values are read
+// through closures, so the menus stay in sync with settings changed elsewhere.
+func (g *Game) buildMenus() {
+	mode := &render.MenuItem{
+		Label:    "Mode",
+		Kind:     render.MenuChoice,
+		Value:    func() string { return g.Mode.Name },
+		OnChange: g.cycleMode,
+	}
+	g.titleMenu = render.NewMenu("SNAKE GAME",
+		&render.MenuItem{
+			Label:    "Continue",
+			OnSelect: g.continueGame,
+			Hidden:   func() bool { return !g.hasSave },
+		},
+		&render.MenuItem{Label: "Play Game", OnSelect: func() {
+			g.leaveDaily()
+			g.startRun()
+		}},
+		mode,
+		&render.MenuItem{Label: "Daily Challenge", OnSelect: g.startDaily},
+		&render.MenuItem{Label: "High Scores", OnSelect: func() {
+			g.highScoresMenu.Reset()
+			g.Screens.Push(&highScoresScreen{g: g}, TransitionSlide)
+		}},
+		&render.MenuItem{Label: "Statistics", OnSelect: g.openStats},
+		&render.MenuItem{Label: "Settings", OnSelect: func() {
+			g.Screens.Push(&settingsScreen{g: g}, TransitionSlide)
+		}},
+		&render.MenuItem{Label: "Level Editor", OnSelect: g.openEditor},
+		&render.MenuItem{Label: "Exit Game", OnSelect: func() {
+			os.Exit(0)
+		}},
+		&render.MenuItem{Kind: render.MenuLabel, Value: g.dailyStatus},
+	)
+
+	// Settings and high scores are pushed on top of whatever opened them.
+	back := func() {
+		g.Screens.Pop(TransitionSlide)
+	}
+	g.settingsMenu = render.NewMenu("SETTINGS",
+		&render.MenuItem{
+			Label:    "Difficulty",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return difficultyByName(g.Difficulties, g.Settings.Difficulty).Name },
+			OnChange: g.cycleDifficulty,
+		},
+		&render.MenuItem{
+			Label:    "Skin",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return g.SpriteManager.Skin.Name },
+			OnChange: g.cycleSkin,
+		},
+		&render.MenuItem{
+			Label:    "Map",
+			Kind:     render.MenuChoice,
+			Value:    g.mapName,
+			OnChange: g.cycleMap,
+		},
+		&render.MenuItem{
+			Label:    "Theme",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return g.Theme.Name },
+			OnChange: g.cycleTheme,
+		},
+		&render.MenuItem{
+			Label: "Ghost (Same Board)",
+			Kind:  render.MenuToggle,
+			Value: func() string { return onOff(g.Settings.Ghost) },
+			OnChange: func(int) {
+				g.Settings.Ghost = !g.Settings.Ghost
+				g.Settings.Save()
+			},
+		},
+		&render.MenuItem{
+			Label: "Smooth",
+			Kind:  render.MenuToggle,
+			Value: func() string { return onOff(g.Settings.Smooth) },
+			OnChange: func(int) {
+				g.Settings.Smooth = !g.Settings.Smooth
+				g.Renderer.Interpolate = g.Settings.Smooth
+				g.Settings.Save()
+			},
+		},
+		&render.MenuItem{
+			Label: "Effects",
+			Kind:  render.MenuToggle,
+			Value: func() string { return onOff(g.Settings.Effects) },
+			OnChange: func(int) {
+				g.Settings.Effects = !g.Settings.Effects
+				g.Effects.Enabled = g.Settings.Effects
+				g.Effects.Clear()
+				g.Settings.Save()
+			},
+		},
+		&render.MenuItem{
+			Label: "Volume",
+			Kind:  render.MenuSlider,
+			Level: func() float64 { return g.Settings.Volume },
+			OnChange: func(step int) {
+				g.Settings.Volume = clamp01(g.Settings.Volume + float64(step)*0.1)
+				g.SoundMan.SetVolume(g.Settings.Volume)
+				g.Settings.Save()
+			},
+		},
+		&render.MenuItem{Label: "Back", OnSelect: back},
+		&render.MenuItem{
+			Kind:   render.MenuLabel,
+			Hidden: func() bool { return g.nextSkinUnlock() == nil },
+			Value: func() string {
+				return fmt.Sprintf("Next skin at score %d", g.nextSkinUnlock().UnlockScore)
+			},
+		},
+	)
+	g.settingsMenu.OnBack = back
+
+	// Runs started from the editor offer a way straight back to it.
+	backToEditor := &render.MenuItem{
+		Label:    "Back to Editor",
+		OnSelect: g.returnToEditor,
+		Hidden:   func() bool { return !g.testPlay },
+	}
+	g.gameOverMenu = render.NewMenu("GAME OVER",
+		&render.MenuItem{
+			Label:    "Rewind",
+			OnSelect: g.rewindFromGameOver,
+			Hidden:   func() bool { return !g.Mode.Rewind || g.rewind.Len() == 0 },
+		},
+		&render.MenuItem{Label: "Play Again", OnSelect: g.startRun},
+		backToEditor,
+		&render.MenuItem{Label: "Main Menu", OnSelect: func() {
+			g.resetGame()
+			g.Screens.Switch(&titleScreen{g: g}, TransitionFade)
+		}},
+		&render.MenuItem{Label: "Exit Game", OnSelect: func() {
+			os.Exit(0)
+		}},
+	)
+
+	g.pauseMenu = render.NewMenu("PAUSED",
+		&render.MenuItem{Label: "Resume", OnSelect: g.resume},
+		&render.MenuItem{Label: "Restart", OnSelect: g.startRun},
+		backToEditor,
+		&render.MenuItem{
+			Label:    "Save & Quit",
+			OnSelect: g.saveAndQuit,
+			Hidden:   func() bool { return !g.canSave() },
+		},
+		&render.MenuItem{Label: "Settings", OnSelect: func() {
+			g.Screens.Push(&settingsScreen{g: g}, TransitionSlide)
+		}},
+		&render.MenuItem{Label: "Quit to Title", OnSelect: func() {
+			g.resetGame()
+			g.Screens.Switch(&titleScreen{g: g}, TransitionFade)
+		}},
+	)
+	g.pauseMenu.OnBack = g.resume
+
+	g.highScoresMenu = render.NewMenu("HIGH SCORES",
+		mode,
+		&render.MenuItem{Label: "Back", OnSelect: back},
+	)
+	g.highScoresMenu.OnBack = back
+
+	g.statsMenu = render.NewMenu("STATISTICS",
+		&render.MenuItem{
+			Label:    "View",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return statsPageNames[g.statsPage] },
+			OnChange: g.cycleStatsPage,
+		},
+		&render.MenuItem{
+			Label:    "Level",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return strconv.Itoa(g.statsLevel) },
+			OnChange: g.cycleStatsLevel,
+			Hidden:   func() bool { return g.statsPage != statsHeatmap || len(g.Profile.Stats.Heatmaps) == 0 },
+		},
+		&render.MenuItem{Label: "Export JSON", OnSelect: func() { g.exportStats(statsJSONFile) }},
+		&render.MenuItem{Label: "Export CSV", OnSelect: func() { g.exportStats(statsCSVFile) }},
+		&render.MenuItem{Label: "Back", OnSelect: back},
+		&render.MenuItem{
+			Kind:   render.MenuLabel,
+			Value:  func() string { return g.statsStatus },
+			Hidden: func() bool { return g.statsStatus == "" },
+		},
+	)
+	g.statsMenu.OnBack = back
+
+	closeEditorMenu := func() {
+		g.Screens.Pop(TransitionNone)
+	}
+	g.editorMenu = render.NewMenu("EDITOR",
+		&render.MenuItem{
+			Label:    "Name",
+			Kind:     render.MenuText,
+			Value:    func() string { return g.Editor.fieldText(&g.Editor.Map.Name) },
+			OnSelect: func() { g.Editor.typing = &g.Editor.Map.Name },
+		},
+		&render.MenuItem{
+			Label:    "Author",
+			Kind:     render.MenuText,
+			Value:    func() string { return g.Editor.fieldText(&g.Editor.Map.Author) },
+			OnSelect: func() { g.Editor.typing = &g.Editor.Map.Author },
+		},
+		&render.MenuItem{
+			Label:    "Width",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return strconv.Itoa(g.Editor.Map.Width) },
+			OnChange: func(step int) { g.resizeEditor(step, 0) },
+		},
+		&render.MenuItem{
+			Label:    "Height",
+			Kind:     render.MenuChoice,
+			Value:    func() string { return strconv.Itoa(g.Editor.Map.Height) },
+			OnChange: func(step int) { g.resizeEditor(0, step) },
+		},
+		&render.MenuItem{Label: "Test Play", OnSelect: g.testPlayEditorMap},
+		&render.MenuItem{Label: "Save", OnSelect: g.saveEditorMap},
+		&render.MenuItem{
+			Label:    "Open",
+			Kind:     render.MenuChoice,
+			Value:    g.editorOpenName,
+			OnChange: g.openEditorMap,
+		},
+		&render.MenuItem{Label: "Back to Editor", OnSelect: closeEditorMenu},
+		&render.MenuItem{Label: "Quit to Title", OnSelect: func() {
+			g.Screens.Switch(&titleScreen{g: g}, TransitionFade)
+		}},
+		&render.MenuItem{
+			Kind:   render.MenuLabel,
+			Hidden: func() bool { return g.Editor.status == "" },
+			Value:  func() string { return g.Editor.status },
+		},
+	)
+	g.editorMenu.OnBack = closeEditorMenu
+}
+
+// startRun begins a new run of the current mode. The map and difficulty chosen in
+// settings are fixed here for the whole run; daily challenges bring their own
+// difficulty and test runs keep the map being edited. The first daily challenge
+// run of the day is the official attempt; it is marked as used straight away.
+func (g *Game) startRun() {
+	if g.Daily != nil {
+		g.setDifficulty(g.Daily.Difficulty)
+	} else {
+		g.setDifficulty(difficultyByName(g.Difficulties, g.Settings.Difficulty))
+	}
+	if !g.testPlay {
+		g.Map = mapByName(g.Maps, g.Settings.Map)
+	}
+	g.resetGame()
+	g.dailyOfficial = false
+	if g.Daily != nil {
+		if _, played := g.Profile.DailyScore(g.Daily.Date); !played {
+			g.dailyOfficial = true
+			g.Profile.RecordDaily(g.Daily.Date, 0)
+		}
+	}
+	g.Screens.Switch(&playingScreen{g: g}, TransitionFade)
+}
+
+// resume closes the pause menu and starts the countdown back into play.
+func (g *Game) resume() {
+	g.Screens.Replace(&countdownScreen{g: g}, TransitionNone)
+}
+
+// nextSkinUnlock returns the first skin still locked for the player's best score.
+func (g *Game) nextSkinUnlock() *render.Skin {
+	for _, s := range render.Skins {
+		if !s.Unlocked(g.Profile.BestScore) {
+			return s
+		}
+	}
+	return nil
+}
+
+// cycleSkin steps to the next unlocked skin in the given direction and saves the choice.
+func (g *Game) cycleSkin(step int) {
+	current := 0
+	for i, skin := range render.Skins {
+		if skin == g.SpriteManager.Skin {
+			current = i
+		}
+	}
+	n := len(render.Skins)
+	for i := 1; i < n; i++ {
+		skin := render.Skins[(current+step*i+n*n)%n]
+		if skin.Unlocked(g.Profile.BestScore) {
+			g.SpriteManager.SetSkin(skin)
+			g.Settings.Skin = skin.Name
+			g.Settings.Save()
+			return
+		}
+	}
+}
+
+// cycleDifficulty steps through the difficulty presets and saves the choice. The new
+// difficulty applies from the next run that is not a daily challenge.
+func (g *Game) cycleDifficulty(step int) {
+	chosen := difficultyByName(g.Difficulties, g.Settings.Difficulty)
+	current := 0
+	for i, d := range g.Difficulties {
+		if d == chosen {
+			current = i
+		}
+	}
+	n := len(g.Difficulties)
+	next := g.Difficulties[(current+step+n)%n]
+	g.Settings.Difficulty = next.Name
+	g.Settings.Save()
+}
+
+// cycleMode steps through the game modes and saves the choice. The new mode's
+// rules apply from the next run.
+func (g *Game) cycleMode(step int) {
+	current := 0
+	for i, m := range Modes {
+		if m == g.Mode {
+			current = i
+		}
+	}
+	n := len(Modes)
+	g.Mode = Modes[(current+step+n)%n]
+	g.Settings.Mode = g.Mode.Name
+	g.Settings.Save()
+}
+
+// cycleTheme steps through the installed themes and saves the choice.
+func (g *Game) cycleTheme(step int) {
+	current := 0
+	for i, theme := range g.Themes {
+		if theme == g.Theme {
+			current = i
+		}
+	}
+	n := len(g.Themes)
+	g.setTheme(g.Themes[(current+step+n)%n])
+	g.Settings.Theme = g.Theme.Name
+	g.Settings.Save()
+}
+
+// onOff formats a boolean setting for display.
+func onOff(v bool) string {
+	if v {
+		return "On"
+	}
+	return "Off"
+}
+
+// clamp01 limits v to the range 0..1.
+func clamp01(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
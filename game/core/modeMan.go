@@ -0,0 +1,88 @@
+package core
+
+import (
+	"fmt"
+	"snakeGame/game/entities"
+	"time"
+)
+
+// Mode is a way to play the game with its own scoring rules and end condition.
+type Mode struct {
+	Name            string
+	Scoring         ScoringRule
+	TimeLimit       time.Duration             // run length for timed modes; 0 = until the snake dies
+	TimeBonus       time.Duration             // time added by each clock eaten
+	TimeBonusChance int                       // one-in-N chance of a clock appearing when an apple is eaten; 0 = never
+	ScoreTable      string                    // high score table in the profile; "" = the main table
+	HazardEvery     int                       // moves between new hazards; 0 = no hazards
+	HazardWarning   int                       // moves a new hazard or wall blinks before it becomes solid
+	ShrinkEvery     int                       // moves between the board shrinking by one ring; 0 = never
+	TickScoreEvery  int                       // score one point per this many moves survived instead of for food; 0 = off
+	Rocks           int                       // solid hazards placed when the run starts
+	FoodWeights     map[entities.FoodKind]int // spawn weight overrides; nil = FoodSpecs weights
+	Rewind          bool                      // holding the rewind key steps back through recent moves
+}
+
+// classicScoring is the scoring of the untimed food-eating modes.
+var classicScoring = ScoringRule{
+	ComboWindow:    15,
+	MaxCombo:       5,
+	QuickMoves:     8,
+	QuickBonus:     2,
+	MilestoneEvery: 10,
+	MilestoneBonus: 10,
+}
+
+// Modes lists every game mode in the order they appear on the title screen.
+var Modes = []*Mode{
+	{
+		Name:    "Classic",
+		Scoring: classicScoring,
+	},
+	timeAttack(60 * time.Second),
+	timeAttack(120 * time.Second),
+	{
+		Name:           "Survival",
+		ScoreTable:     "survival",
+		HazardEvery:    25,
+		HazardWarning:  12,
+		ShrinkEvery:    300,
+		TickScoreEvery: 5,
+	},
+	// Practice plays like Classic with rewind. Runs that never rewind still count
+	// towards the main high score table.
+	{
+		Name:    "Practice",
+		Scoring: classicScoring,
+		Rewind:  true,
+	},
+}
+
+// timeAttack builds a Time Attack mode of the given length. Each length keeps its own
+// high score table, since scores from different lengths are not comparable.
+func timeAttack(limit time.Duration) *Mode {
+	seconds := int(limit.Seconds())
+	return &Mode{
+		Name: fmt.Sprintf("Time Attack %d:%02d", seconds/60, seconds%60),
+		Scoring: ScoringRule{
+			ComboWindow: 10,
+			MaxCombo:    8,
+			QuickMoves:  6,
+			QuickBonus:  3,
+		},
+		TimeLimit:       limit,
+		TimeBonus:       5 * time.Second,
+		TimeBonusChance: 3,
+		ScoreTable:      fmt.Sprintf("time_attack_%d", seconds),
+	}
+}
+
+// modeByName returns the mode with the given name, falling back to the first one.
+func modeByName(name string) *Mode {
+	for _, m := range Modes {
+		if m.Name == name {
+			return m
+		}
+	}
+	return Modes[0]
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"image"
+	"snakeGame/game/entities"
+	"snakeGame/game/render"
+	"snakeGame/game/ui"
+)
+
+// slowTimeFactor is the speed multiplier while slow-time is active.
+const slowTimeFactor = 0.6
+
+// activePowerUp is a collected power-up whose effect is running.
+type activePowerUp struct {
+	remaining int // moves left
+	stacks    int // pickups counted towards the effect, up to MaxStacks
+}
+
+// PowerUpManager tracks the effects of collected power-ups. Picking up a kind that
+// is already active refreshes its duration and adds a stack if the kind allows it:
+// shields stack as extra charges, multipliers as extra score factors.
+type PowerUpManager struct {
+	active map[entities.PowerUpKind]*activePowerUp
+}
+
+// NewPowerUpManager creates a manager with no active effects.
+func NewPowerUpManager() *PowerUpManager {
+	return &PowerUpManager{active: make(map[entities.PowerUpKind]*activePowerUp)}
+}
+
+// Activate starts or refreshes the effect of a collected power-up.
+func (p *PowerUpManager) Activate(kind entities.PowerUpKind) {
+	spec := entities.PowerUpSpecs[kind]
+	a, ok := p.active[kind]
+	if !ok {
+		a = &activePowerUp{}
+		p.active[kind] = a
+	}
+	a.remaining = spec.Duration
+	if a.stacks < spec.MaxStacks {
+		a.stacks++
+	}
+}
+
+// Active reports whether a power-up's effect is running.
+func (p *PowerUpManager) Active(kind entities.PowerUpKind) bool {
+	_, ok := p.active[kind]
+	return ok
+}
+
+// Multiplier returns the factor applied to points scored: 1 plus one per stack.
+func (p *PowerUpManager) Multiplier() int {
+	if a, ok := p.active[entities.PowerMultiplier]; ok {
+		return 1 + a.stacks
+	}
+	return 1
+}
+
+// UseShield spends one shield charge, returning false if there is none.
+func (p *PowerUpManager) UseShield() bool {
+	a, ok := p.active[entities.PowerShield]
+	if !ok {
+		return false
+	}
+	a.stacks--
+	if a.stacks == 0 {
+		delete(p.active, entities.PowerShield)
+	}
+	return true
+}
+
+// Tick counts down every active effect by one move and drops those that run out.
+func (p *PowerUpManager) Tick() {
+	for kind, a := range p.active {
+		a.remaining--
+		if a.remaining <= 0 {
+			delete(p.active, kind)
+		}
+	}
+}
+
+// Reset ends every effect, used on restart.
+func (p *PowerUpManager) Reset() {
+	p.active = make(map[entities.PowerUpKind]*activePowerUp)
+}
+
+// Timers lists the running effects for the HUD in a stable order, with icons from the theme.
+func (p *PowerUpManager) Timers(theme *ui.Theme) []render.HUDTimer {
+	var timers []render.HUDTimer
+	for _, kind := range entities.PowerUpKinds {
+		a, ok := p.active[kind]
+		if !ok {
+			continue
+		}
+		spec := entities.PowerUpSpecs[kind]
+		timers = append(timers, render.HUDTimer{
+			Label:     spec.Name,
+			Icon:      theme.PowerUps[spec.Sprite],
+			Stacks:    a.stacks,
+			Remaining: float64(a.remaining) / float64(spec.Duration),
+		})
+	}
+	return timers
+}
+
+// powerUpChance is the one-in-N chance of a power-up appearing when an apple is eaten.
+const powerUpChance = 4
+
+// magnetRadius is how far, in cells, the magnet reaches for food.
+const magnetRadius = 5
+
+// powerUpAt returns the power-up waiting at pos, or nil if there is none.
+func (g *Game) powerUpAt(pos image.Point) *entities.PowerUp {
+	for _, item := range g.PowerUpItems {
+		if item.Pos == pos {
+			return item
+		}
+	}
+	return nil
+}
+
+// expirePowerUps counts down power-ups waiting on the board and removes those that run out.
+func (g *Game) expirePowerUps() {
+	kept := g.PowerUpItems[:0]
+	for _, item := range g.PowerUpItems {
+		if !item.Tick() {
+			kept = append(kept, item)
+		}
+	}
+	g.PowerUpItems = kept
+}
+
+// collectPowerUp takes a power-up off the board and starts its effect.
+func (g *Game) collectPowerUp(item *entities.PowerUp) {
+	for i, it := range g.PowerUpItems {
+		if it == item {
+			g.PowerUpItems = append(g.PowerUpItems[:i], g.PowerUpItems[i+1:]...)
+			break
+		}
+	}
+	g.PowerUps.Activate(item.Kind)
+	spec := item.Spec()
+	if item.Kind == entities.PowerSlowTime {
+		g.Speed.SetModifier("slow_time", slowTimeFactor, spec.Duration)
+	}
+
+	cx := float64(item.Pos.X*g.cellSize + g.cellSize/2)
+	cy := float64(item.Pos.Y*g.cellSize + g.cellSize/2 + HUDHeight(g.cellSize))
+	g.Effects.Burst(cx, cy, 16, g.Theme.Palette.Accent)
+	g.Effects.FloatText(spec.Name, cx, cy-float64(g.cellSize))
+	g.SoundMan.PlaySound("bite")
+}
+
+// pullFood moves apples within the magnet's reach one cell towards the snake's head.
+func (g *Game) pullFood() {
+	head := g.Snake.HeadPos()
+	for _, food := range g.Foods {
+		if food.Kind != entities.FoodApple && food.Kind != entities.FoodGoldenApple {
+			continue
+		}
+		d := head.Sub(food.Pos)
+		if abs(d.X)+abs(d.Y) > magnetRadius {
+			continue
+		}
+		step := image.Pt(entities.Sign(d.X), 0)
+		if abs(d.Y) > abs(d.X) {
+			step = image.Pt(0, entities.Sign(d.Y))
+		}
+		if target := food.Pos.Add(step); !g.cellBlocked(target) {
+			food.Pos = target
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+	"sort"
+)
+
+const profileFile = "profile.json"
+
+// ProfileManager tracks player progress that carries over between games, such as the
+// best score used to unlock skins.
+type ProfileManager struct {
+	BestScore  int              `json:"best_score"`
+	HighScores []int            `json:"high_scores"`           // best scores first, at most maxHighScores
+	ModeScores map[string][]int `json:"mode_scores,omitempty"` // tables for modes with their own, by table name
+	Daily      map[string]int   `json:"daily,omitempty"`       // official daily challenge score by date
+	Stats      LifetimeStats    `json:"stats"`
+	path       string
+}
+
+// maxHighScores is how many entries the high score table keeps.
+const maxHighScores = 10
+
+// NewProfileManager loads the saved profile, starting fresh if none exists.
+func NewProfileManager() *ProfileManager {
+	p := &ProfileManager{path: profileFile}
+	p.Load()
+	return p
+}
+
+// Load reads the profile from disk. Missing or invalid files leave a fresh profile.
+func (p *ProfileManager) Load() {
+	data, err := os.ReadFile(p.path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		log.Printf("Failed to parse profile: %v", err)
+	}
+}
+
+// Save writes the profile to disk.
+func (p *ProfileManager) Save() {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode profile: %v", err)
+		return
+	}
+	if err := os.WriteFile(p.path, data, 0o644); err != nil {
+		log.Printf("Failed to save profile: %v", err)
+	}
+}
+
+// ScoresFor returns the high score table with the given name; "" is the main table.
+func (p *ProfileManager) ScoresFor(table string) []int {
+	if table == "" {
+		return p.HighScores
+	}
+	return p.ModeScores[table]
+}
+
+// RecordScore adds a finished run to the named high score table ("" for the main
+// one), updates the best score and returns true if it was beaten.
+func (p *ProfileManager) RecordScore(table string, score int) bool {
+	if table == "" {
+		p.HighScores = insertHighScore(p.HighScores, score)
+	} else {
+		if p.ModeScores == nil {
+			p.ModeScores = make(map[string][]int)
+		}
+		p.ModeScores[table] = insertHighScore(p.ModeScores[table], score)
+	}
+	beaten := score > p.BestScore
+	if beaten {
+		p.BestScore = score
+	}
+	p.Save()
+	return beaten
+}
+
+// DailyScore returns the official score for a daily challenge date and whether
+// that day's attempt has been used.
+func (p *ProfileManager) DailyScore(date string) (int, bool) {
+	score, ok := p.Daily[date]
+	return score, ok
+}
+
+// RecordDaily stores the official score for a daily challenge date. It is first
+// called with 0 when the attempt starts, so quitting does not grant a retry.
+func (p *ProfileManager) RecordDaily(date string, score int) {
+	if p.Daily == nil {
+		p.Daily = make(map[string]int)
+	}
+	p.Daily[date] = score
+	p.Save()
+}
+
+// insertHighScore places score into a descending table, keeping at most maxHighScores.
+func insertHighScore(table []int, score int) []int {
+	i := sort.Search(len(table), func(i int) bool { return table[i] < score })
+	table = append(table, 0)
+	copy(table[i+1:], table[i:])
+	table[i] = score
+	if len(table) > maxHighScores {
+		table = table[:maxHighScores]
+	}
+	return table
+}
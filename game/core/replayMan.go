@@ -0,0 +1,59 @@
+package core
+
+import (
+	"image"
+	"snakeGame/game/entities"
+)
+
+// InputEvent is a change of the snake's pending direction, taking effect on the given move.
+type InputEvent struct {
+	Tick int `json:"tick"`
+	DX   int `json:"dx"`
+	DY   int `json:"dy"`
+}
+
+// Dir returns the direction the event sets.
+func (e InputEvent) Dir() image.Point {
+	return image.Pt(e.DX, e.DY)
+}
+
+// InputLog records the direction changes of a run. Together with the run's seed it
+// is enough to simulate the run again move for move.
+type InputLog struct {
+	Events []InputEvent
+	last   image.Point
+}
+
+// Reset starts an empty log for a snake whose pending direction is start.
+func (l *InputLog) Reset(start image.Point) {
+	l.Events = nil
+	l.last = start
+}
+
+// Record notes the pending direction at the start of a move if it has changed.
+func (l *InputLog) Record(tick int, dir image.Point) {
+	if dir == l.last {
+		return
+	}
+	l.last = dir
+	l.Events = append(l.Events, InputEvent{Tick: tick, DX: dir.X, DY: dir.Y})
+}
+
+// InputPlayback feeds a recorded input log back into a snake.
+type InputPlayback struct {
+	events []InputEvent
+	next   int
+}
+
+// NewInputPlayback plays back the given events from the start.
+func NewInputPlayback(events []InputEvent) *InputPlayback {
+	return &InputPlayback{events: events}
+}
+
+// Apply sets the snake's pending direction from every event due at tick.
+func (p *InputPlayback) Apply(tick int, snake *entities.SnakeController) {
+	for p.next < len(p.events) && p.events[p.next].Tick <= tick {
+		snake.PendingDir = p.events[p.next].Dir()
+		p.next++
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"log"
+	"time"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Rewind tuning for practice mode.
+const (
+	rewindWindow   = 5 * time.Second // how far back the snapshots reach
+	rewindCapacity = 600             // snapshots kept at most, enough for the window at top speed
+	rewindFrames   = 3               // frames each move is shown for while rewinding
+	rewindOnDeath  = time.Second     // how far the game-over menu's Rewind goes back
+)
+
+// rewindBuffer is a ring of snapshots taken before each move, oldest first.
+type rewindBuffer struct {
+	states []*runState
+	start  int // index of the oldest snapshot
+	count  int
+}
+
+// Push adds the newest snapshot, dropping the oldest ones once the buffer is full or
+// they fall out of the rewind window.
+func (b *rewindBuffer) Push(r *runState) {
+	if b.states == nil {
+		b.states = make([]*runState, rewindCapacity)
+	}
+	for b.count > 0 && (b.count == len(b.states) || r.Elapsed-b.states[b.start].Elapsed > rewindWindow) {
+		b.states[b.start] = nil
+		b.start = (b.start + 1) % len(b.states)
+		b.count--
+	}
+	b.states[(b.start+b.count)%len(b.states)] = r
+	b.count++
+}
+
+// Pop removes and returns the newest snapshot, or nil when the buffer is empty.
+func (b *rewindBuffer) Pop() *runState {
+	if b.count == 0 {
+		return nil
+	}
+	b.count--
+	i := (b.start + b.count) % len(b.states)
+	r := b.states[i]
+	b.states[i] = nil
+	return r
+}
+
+// Len returns the number of snapshots held.
+func (b *rewindBuffer) Len() int {
+	return b.count
+}
+
+// Clear drops every snapshot.
+func (b *rewindBuffer) Clear() {
+	clear(b.states)
+	b.start, b.count = 0, 0
+}
+
+// rewindHeld reports whether R or a gamepad's left shoulder button is held down.
+func (g *Game) rewindHeld() bool {
+	if ebiten.IsKeyPressed(ebiten.KeyR) {
+		return true
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if ebiten.IsStandardGamepadButtonPressed(id, ebiten.StandardGamepadButtonFrontTopLeft) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateRewind steps back one move every few frames while the rewind key is held in
+// practice mode, including during the death sequence. It reports whether the key is
+// held, in which case the simulation waits.
+func (g *Game) updateRewind() bool {
+	if !g.Mode.Rewind || !g.rewindHeld() {
+		g.rewinding = 0
+		return false
+	}
+	if g.rewinding%rewindFrames == 0 {
+		g.rewindBy(0)
+	}
+	g.rewinding++
+	// Time spent rewinding is not played back once the key is released.
+	g.Speed.ResetClock()
+	return true
+}
+
+// rewindBy restores the newest snapshot taken at least d before now, or the oldest
+// one kept, and reports whether there was one. The run is flagged as rewound.
+func (g *Game) rewindBy(d time.Duration) bool {
+	target := g.State.Elapsed - d
+	var r *runState
+	for g.rewind.Len() > 0 {
+		r = g.rewind.Pop()
+		if r.Elapsed <= target {
+			break
+		}
+	}
+	if r == nil {
+		return false
+	}
+	crashed := g.State.GameOver
+	if err := g.restoreRun(r); err != nil {
+		log.Printf("Failed to rewind: %v", err)
+		return false
+	}
+	g.rewound = true
+	if crashed && g.Screens.Current() == ScreenPlaying {
+		g.SoundMan.PlayLoopingSound("bgm")
+	}
+	return true
+}
+
+// rewindFromGameOver goes back a moment before the crash and resumes after a countdown.
+func (g *Game) rewindFromGameOver() {
+	if !g.rewindBy(rewindOnDeath) {
+		return
+	}
+	g.State.SetPaused(true)
+	g.Screens.Replace(&countdownScreen{g: g}, TransitionNone)
+}
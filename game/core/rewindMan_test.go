@@ -0,0 +1,112 @@
+package core
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRewindBuffer(t *testing.T) {
+	ms := time.Millisecond
+	// every pushes n snapshots step apart, starting at from.
+	every := func(from, step time.Duration, n int) []time.Duration {
+		var times []time.Duration
+		for i := range n {
+			times = append(times, from+time.Duration(i)*step)
+		}
+		return times
+	}
+	tests := []struct {
+		name   string
+		pushes []time.Duration // play time of each snapshot pushed; -1 pops one instead
+		want   []time.Duration // snapshots left, newest first
+	}{
+		{"empty", nil, nil},
+		{"newest comes back first", every(0, 100*ms, 3), every(200*ms, -100*ms, 3)},
+		{"full buffer drops the oldest", every(0, ms, rewindCapacity+5), every(time.Duration(rewindCapacity+4)*ms, -ms, rewindCapacity)},
+		{"older than the window", every(0, time.Second, 11), every(10*time.Second, -time.Second, 6)},
+		{"pops in between", []time.Duration{0, 100 * ms, -1, 200 * ms, 300 * ms, -1, -1, 400 * ms}, []time.Duration{400 * ms, 0}},
+		{"pop on empty", []time.Duration{-1, 100 * ms}, []time.Duration{100 * ms}},
+		{"wraps around the ring", append(append(every(0, ms, rewindCapacity), -1, -1, -1), every(time.Second, ms, 10)...),
+			append(every(time.Second+9*ms, -ms, 10), every(time.Duration(rewindCapacity-4)*ms, -ms, rewindCapacity-10)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b rewindBuffer
+			for _, at := range tt.pushes {
+				if at < 0 {
+					b.Pop()
+					continue
+				}
+				b.Push(&runState{Elapsed: at})
+			}
+			if b.Len() != len(tt.want) {
+				t.Fatalf("%d snapshots, want %d", b.Len(), len(tt.want))
+			}
+			var got []time.Duration
+			for r := b.Pop(); r != nil; r = b.Pop() {
+				got = append(got, r.Elapsed)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("snapshots = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRewindBy(t *testing.T) {
+	const frame = 100 * time.Millisecond
+	tests := []struct {
+		name  string
+		moves int
+		back  time.Duration
+		to    int // move whose snapshot is restored
+	}{
+		{"last move", 30, 0, 29},
+		{"one second", 30, time.Second, 19},
+		{"between snapshots", 30, 1050 * time.Millisecond, 18},
+		{"further than played", 30, 10 * time.Second, 0},
+		{"further than the window", 80, 10 * time.Second, 29},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newHeadlessGame(20, 20, modeByName("Practice"), nil)
+			g.resetRun(11)
+			var before []string
+			for range tt.moves {
+				g.State.Elapsed += frame
+				steerToApple(g)
+				before = append(before, snapshotJSON(t, g))
+				g.tick()
+				if g.State.GameOver {
+					t.Fatalf("crashed after %d moves", g.State.Ticks)
+				}
+			}
+
+			if !g.rewindBy(tt.back) {
+				t.Fatal("nothing to rewind to")
+			}
+			if !g.rewound {
+				t.Error("run not flagged as rewound")
+			}
+			if got := snapshotJSON(t, g); got != before[tt.to] {
+				t.Errorf("restored\n%s\nwant\n%s", got, before[tt.to])
+			}
+		})
+	}
+}
+
+// snapshotJSON encodes the run in progress and its input log for comparison,
+// leaving out whether it has been rewound.
+func snapshotJSON(t *testing.T, g *Game) string {
+	t.Helper()
+	r := g.captureRun()
+	r.Rewound = false
+	r.Inputs = g.Inputs.Events
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
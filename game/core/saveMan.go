@@ -0,0 +1,428 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"log"
+	"math/rand"
+	"os"
+	"snakeGame/game/entities"
+	"sort"
+	"time"
+)
+
+// saveFile keeps a run in progress between sessions.
+const saveFile = "savegame.json"
+
+// saveVersion is bumped whenever the save format or the rules change in a way that
+// would make an old save continue differently.
+const saveVersion = 2
+
+// SavedGame is a run in progress written to disk, along with the choices it was
+// started with.
+type SavedGame struct {
+	Version    int      `json:"version"`
+	Mode       string   `json:"mode"`
+	Difficulty string   `json:"difficulty"`
+	Map        string   `json:"map,omitempty"` // name of the map the run started on; "" = open board
+	Run        runState `json:"run"`
+}
+
+// runState is everything needed to continue a run exactly where it stopped: the
+// board, the snake, every counter and the random generator's position.
+type runState struct {
+	GridWidth    int                 `json:"grid_width"`
+	GridHeight   int                 `json:"grid_height"`
+	Seed         int64               `json:"seed"`
+	RNGDrawn     uint64              `json:"rng_drawn"` // values drawn from the seeded generator so far
+	Snake        snakeState          `json:"snake"`
+	Foods        []entities.Food     `json:"foods"`
+	PowerUpItems []entities.PowerUp  `json:"power_up_items,omitempty"`
+	PowerUps     []powerUpState      `json:"power_ups,omitempty"`
+	Hazards      hazardState         `json:"hazards"`
+	Movers       []moverState        `json:"movers,omitempty"`
+	Portals      []entities.Portal   `json:"portals,omitempty"`
+	FoodZone     []image.Point       `json:"food_zone,omitempty"`
+	Score        int                 `json:"score"`
+	Level        int                 `json:"level"`
+	Ticks        int                 `json:"ticks"`
+	Elapsed      time.Duration       `json:"elapsed"`
+	TimeLeft     time.Duration       `json:"time_left,omitempty"`
+	Accumulator  time.Duration       `json:"accumulator"` // time towards the next move
+	Modifiers    map[string]modState `json:"speed_modifiers,omitempty"`
+	Combo        int                 `json:"combo"`
+	SinceBite    int                 `json:"since_bite"`
+	Milestone    int                 `json:"milestone"`
+	Breakdown    ScoreBreakdown      `json:"breakdown"`
+	Inputs       []InputEvent        `json:"inputs,omitempty"` // only in save files
+	LastInput    image.Point         `json:"last_input"`
+	Rewound      bool                `json:"rewound,omitempty"` // practice runs that rewound stay out of high scores
+	Apples       int                 `json:"apples"`
+
+	// Rewind snapshots share the game's input log rather than copying it, and
+	// only note how long it was.
+	inputCount int
+}
+
+// snakeState is the snake's segments from head to tail with how each is drawn.
+type snakeState struct {
+	Segments   []segmentState `json:"segments"`
+	Dir        image.Point    `json:"dir"`
+	PendingDir image.Point    `json:"pending_dir"`
+	Growing    bool           `json:"growing"`
+}
+
+type segmentState struct {
+	Pos      image.Point       `json:"pos"`
+	Tile     entities.TileType `json:"tile"`
+	Rotation float64           `json:"rotation"`
+	Dir      image.Point       `json:"dir"`
+}
+
+type powerUpState struct {
+	Kind      entities.PowerUpKind `json:"kind"`
+	Remaining int                  `json:"remaining"`
+	Stacks    int                  `json:"stacks"`
+}
+
+type hazardState struct {
+	Hazards       []entities.Hazard `json:"hazards,omitempty"`
+	Inset         int               `json:"inset"`
+	ShrinkWarning int               `json:"shrink_warning"`
+	SinceHazard   int               `json:"since_hazard"`
+	SinceShrink   int               `json:"since_shrink"`
+}
+
+type moverState struct {
+	Mover    entities.Mover         `json:"mover"`
+	Progress entities.MoverProgress `json:"progress"`
+}
+
+type modState struct {
+	Factor float64 `json:"factor"`
+	Moves  int     `json:"moves"`
+}
+
+// countingSource is the gameplay random source. It counts the values drawn so that a
+// saved run can rebuild the generator's exact state from its seed.
+type countingSource struct {
+	src   rand.Source64
+	drawn uint64
+}
+
+func newCountingSource(seed int64) *countingSource {
+	return &countingSource{src: rand.NewSource(seed).(rand.Source64)}
+}
+
+func (s *countingSource) Int63() int64 {
+	s.drawn++
+	return s.src.Int63()
+}
+
+func (s *countingSource) Uint64() uint64 {
+	s.drawn++
+	return s.src.Uint64()
+}
+
+func (s *countingSource) Seed(seed int64) {
+	s.src.Seed(seed)
+	s.drawn = 0
+}
+
+// skip draws and discards n values, catching a fresh source up with a saved one.
+func (s *countingSource) skip(n uint64) {
+	for s.drawn < n {
+		s.Int63()
+	}
+}
+
+// seedRun starts the gameplay random generator for a run, drawn values already skipped.
+func (g *Game) seedRun(seed int64, drawn uint64) {
+	g.Seed = seed
+	g.rngSource = newCountingSource(seed)
+	g.rngSource.skip(drawn)
+	g.rng = rand.New(g.rngSource)
+}
+
+// captureRun takes a snapshot of the run in progress. It notes only the length of
+// the input log, since rewinding just truncates the log back to it.
+func (g *Game) captureRun() *runState {
+	r := &runState{
+		GridWidth:  g.gridWidth,
+		GridHeight: g.gridHeight,
+		Seed:       g.Seed,
+		RNGDrawn:   g.rngSource.drawn,
+		Snake: snakeState{
+			Dir:        g.Snake.Dir,
+			PendingDir: g.Snake.PendingDir,
+			Growing:    g.Snake.Growing,
+		},
+		Hazards: hazardState{
+			Inset:         g.Hazards.Inset,
+			ShrinkWarning: g.Hazards.ShrinkWarning,
+			SinceHazard:   g.Hazards.sinceHazard,
+			SinceShrink:   g.Hazards.sinceShrink,
+		},
+		Portals:     append([]entities.Portal(nil), g.Portals...),
+		Score:       g.State.Score,
+		Level:       g.State.Level,
+		Ticks:       g.State.Ticks,
+		Elapsed:     g.State.Elapsed,
+		TimeLeft:    g.State.TimeLeft,
+		Accumulator: g.Speed.accumulator,
+		Combo:       g.Scoring.Combo,
+		SinceBite:   g.Scoring.sinceBite,
+		Milestone:   g.Scoring.milestone,
+		Breakdown:   g.Scoring.Breakdown,
+		LastInput:   g.Inputs.last,
+		Rewound:     g.rewound,
+		Apples:      g.apples,
+		inputCount:  len(g.Inputs.Events),
+	}
+	for seg := g.Snake.Head; seg != nil; seg = seg.Next {
+		r.Snake.Segments = append(r.Snake.Segments, segmentState{Pos: seg.Pos, Tile: seg.Tile, Rotation: seg.Rotation, Dir: seg.Dir})
+	}
+	for _, food := range g.Foods {
+		r.Foods = append(r.Foods, *food)
+	}
+	for _, item := range g.PowerUpItems {
+		r.PowerUpItems = append(r.PowerUpItems, *item)
+	}
+	for _, kind := range entities.PowerUpKinds {
+		if a, ok := g.PowerUps.active[kind]; ok {
+			r.PowerUps = append(r.PowerUps, powerUpState{Kind: kind, Remaining: a.remaining, Stacks: a.stacks})
+		}
+	}
+	for _, hz := range g.Hazards.Hazards {
+		r.Hazards.Hazards = append(r.Hazards.Hazards, *hz)
+	}
+	for _, m := range g.Movers {
+		r.Movers = append(r.Movers, moverState{Mover: *m, Progress: m.Progress()})
+	}
+	for pos := range g.foodZone {
+		r.FoodZone = append(r.FoodZone, pos)
+	}
+	// Sorted so that the same run always saves the same file.
+	sort.Slice(r.FoodZone, func(i, j int) bool {
+		a, b := r.FoodZone[i], r.FoodZone[j]
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+	for source, m := range g.Speed.modifiers {
+		if r.Modifiers == nil {
+			r.Modifiers = make(map[string]modState)
+		}
+		r.Modifiers[source] = modState{Factor: m.factor, Moves: m.moves}
+	}
+	return r
+}
+
+// restoreRun replaces the current run with a snapshot. The mode and difficulty
+// must already be the ones the snapshot was taken with. A snapshot from a save
+// file brings its own input log; a rewind snapshot cuts the current one back.
+func (g *Game) restoreRun(r *runState) error {
+	if r.GridWidth <= 0 || r.GridHeight <= 0 {
+		return fmt.Errorf("invalid grid %dx%d", r.GridWidth, r.GridHeight)
+	}
+	if len(r.Snake.Segments) < 2 {
+		return fmt.Errorf("snake has %d segments", len(r.Snake.Segments))
+	}
+	g.setGridSize(r.GridWidth, r.GridHeight)
+	g.seedRun(r.Seed, r.RNGDrawn)
+
+	g.Snake = &entities.SnakeController{
+		Dir:        r.Snake.Dir,
+		PendingDir: r.Snake.PendingDir,
+		GridWidth:  r.GridWidth,
+		GridHeight: r.GridHeight,
+		Growing:    r.Snake.Growing,
+	}
+	var prev *entities.SnakeSegment
+	for _, s := range r.Snake.Segments {
+		seg := &entities.SnakeSegment{Pos: s.Pos, Tile: s.Tile, Rotation: s.Rotation, Dir: s.Dir, Prev: prev}
+		if prev == nil {
+			g.Snake.Head = seg
+		} else {
+			prev.Next = seg
+		}
+		prev = seg
+	}
+	g.Snake.Tail = prev
+
+	g.Foods = nil
+	for _, food := range r.Foods {
+		g.Foods = append(g.Foods, &food)
+	}
+	g.PowerUpItems = nil
+	for _, item := range r.PowerUpItems {
+		g.PowerUpItems = append(g.PowerUpItems, &item)
+	}
+	g.PowerUps.Reset()
+	for _, p := range r.PowerUps {
+		g.PowerUps.active[p.Kind] = &activePowerUp{remaining: p.Remaining, stacks: p.Stacks}
+	}
+	g.Hazards.Reset()
+	for _, hz := range r.Hazards.Hazards {
+		g.Hazards.Hazards = append(g.Hazards.Hazards, &hz)
+	}
+	g.Hazards.Inset = r.Hazards.Inset
+	g.Hazards.ShrinkWarning = r.Hazards.ShrinkWarning
+	g.Hazards.sinceHazard = r.Hazards.SinceHazard
+	g.Hazards.sinceShrink = r.Hazards.SinceShrink
+	g.Movers = nil
+	for _, m := range r.Movers {
+		mover := m.Mover
+		mover.SetProgress(m.Progress)
+		g.Movers = append(g.Movers, &mover)
+	}
+	g.Portals = append([]entities.Portal(nil), r.Portals...)
+	g.foodZone = nil
+	for _, pos := range r.FoodZone {
+		if g.foodZone == nil {
+			g.foodZone = make(map[image.Point]bool)
+		}
+		g.foodZone[pos] = true
+	}
+
+	g.State.Reset()
+	g.State.Score = r.Score
+	g.State.Level = r.Level
+	g.State.Ticks = r.Ticks
+	g.State.Elapsed = r.Elapsed
+	g.State.TimeLeft = r.TimeLeft
+	g.Scoring.Rule = g.Mode.Scoring
+	g.Scoring.Combo = r.Combo
+	g.Scoring.sinceBite = r.SinceBite
+	g.Scoring.milestone = r.Milestone
+	g.Scoring.Breakdown = r.Breakdown
+	g.Speed.Reset()
+	g.Speed.accumulator = r.Accumulator
+	g.Speed.modifiers = nil
+	for source, m := range r.Modifiers {
+		if g.Speed.modifiers == nil {
+			g.Speed.modifiers = make(map[string]*speedModifier)
+		}
+		g.Speed.modifiers[source] = &speedModifier{factor: m.Factor, moves: m.Moves}
+	}
+	g.Speed.AdjustSpeedByLevel(g.State.Level)
+	if r.Inputs != nil {
+		g.Inputs.Events = append([]InputEvent(nil), r.Inputs...)
+	} else {
+		g.Inputs.Events = g.Inputs.Events[:r.inputCount]
+	}
+	g.Inputs.last = r.LastInput
+	g.rewound = r.Rewound
+	g.apples = r.Apples
+	g.Effects.Clear()
+	g.deathFrame = 0
+	g.syncGhost()
+	return nil
+}
+
+// canSave reports whether a run is in progress that can be saved. Daily challenges
+// and test runs from the editor are never saved.
+func (g *Game) canSave() bool {
+	return g.Screens.Contains(ScreenPlaying) && !g.State.GameOver && !g.State.TimeUp &&
+		g.Daily == nil && !g.testPlay
+}
+
+// saveGame writes the run in progress to the save file.
+func (g *Game) saveGame() {
+	saved := SavedGame{
+		Version:    saveVersion,
+		Mode:       g.Mode.Name,
+		Difficulty: g.Difficulty.Name,
+		Run:        *g.captureRun(),
+	}
+	saved.Run.Inputs = g.Inputs.Events
+	if g.Map != nil {
+		saved.Map = g.Map.Name
+	}
+	data, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode saved game: %v", err)
+		return
+	}
+	if err := os.WriteFile(saveFile, data, 0o644); err != nil {
+		log.Printf("Failed to save game: %v", err)
+		return
+	}
+	g.hasSave = true
+	log.Printf("Game saved to %s", saveFile)
+}
+
+// saveAndQuit saves the run from the pause menu and returns to the title screen.
+func (g *Game) saveAndQuit() {
+	g.saveGame()
+	g.resetGame()
+	g.Screens.Switch(&titleScreen{g: g}, TransitionFade)
+}
+
+// continueGame restores the saved run and resumes it after a countdown. A save is
+// continued once: the file is removed as soon as it has been read.
+func (g *Game) continueGame() {
+	data, err := os.ReadFile(saveFile)
+	g.discardSave()
+	if err != nil {
+		log.Printf("Failed to read saved game: %v", err)
+		return
+	}
+	var saved SavedGame
+	if err := json.Unmarshal(data, &saved); err != nil {
+		log.Printf("Failed to parse saved game: %v", err)
+		return
+	}
+	if err := g.restoreSavedGame(&saved); err != nil {
+		log.Printf("Failed to restore saved game: %v", err)
+		g.resetGame()
+		return
+	}
+	g.State.SetPaused(true)
+	g.Screens.Switch(&playingScreen{g: g}, TransitionNone)
+	g.Screens.Push(&countdownScreen{g: g}, TransitionNone)
+}
+
+// restoreSavedGame brings back a saved run with the mode, difficulty and map it was
+// played with, which also become the current choices in settings.
+func (g *Game) restoreSavedGame(saved *SavedGame) error {
+	if saved.Version != saveVersion {
+		return fmt.Errorf("save version %d, this build continues version %d", saved.Version, saveVersion)
+	}
+	mode := modeByName(saved.Mode)
+	if mode.Name != saved.Mode {
+		return fmt.Errorf("unknown mode %q", saved.Mode)
+	}
+	difficulty := difficultyByName(g.Difficulties, saved.Difficulty)
+	if difficulty.Name != saved.Difficulty {
+		return fmt.Errorf("unknown difficulty %q", saved.Difficulty)
+	}
+	g.leaveDaily()
+	g.leaveTestPlay()
+	g.Mode = mode
+	g.setDifficulty(difficulty)
+	// The board comes from the save itself; the map only matters for restarts.
+	g.Map = mapByName(g.Maps, saved.Map)
+	g.Settings.Mode = mode.Name
+	g.Settings.Difficulty = difficulty.Name
+	g.Settings.Map = ""
+	if g.Map != nil {
+		g.Settings.Map = g.Map.Name
+	}
+	g.Settings.Save()
+	g.rewind.Clear()
+	return g.restoreRun(&saved.Run)
+}
+
+// discardSave removes the save file.
+func (g *Game) discardSave() {
+	g.hasSave = false
+	if err := os.Remove(saveFile); err != nil && !os.IsNotExist(err) {
+		log.Printf("Failed to remove saved game: %v", err)
+	}
+}
+
+// saveExists reports whether there is a saved run to continue.
+func saveExists() bool {
+	_, err := os.Stat(saveFile)
+	return err == nil
+}
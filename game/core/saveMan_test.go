@@ -0,0 +1,215 @@
+package core
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestCountingSource(t *testing.T) {
+	tests := []struct {
+		name  string
+		seed  int64
+		draws func(r *rand.Rand)
+	}{
+		{"nothing drawn", 1, func(r *rand.Rand) {}},
+		{"a few cells", 42, func(r *rand.Rand) {
+			for range 10 {
+				r.Intn(20)
+			}
+		}},
+		{"mixed draws", -7, func(r *rand.Rand) {
+			r.Intn(1000)
+			r.Uint64()
+			r.Float64()
+			r.Perm(5)
+			r.Int63n(1 << 40)
+		}},
+		{"long run", 1_700_000_000, func(r *rand.Rand) {
+			for i := range 5000 {
+				r.Intn(i + 1)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src := newCountingSource(tt.seed)
+			r := rand.New(src)
+			tt.draws(r)
+
+			caught := newCountingSource(tt.seed)
+			caught.skip(src.drawn)
+			resumed := rand.New(caught)
+			for i := range 20 {
+				if got, want := resumed.Int63(), r.Int63(); got != want {
+					t.Fatalf("draw %d after %d skipped = %d, want %d", i, src.drawn, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	arena := &Map{
+		Name:    "Arena",
+		Walls:   []cell{{9, 5}, {10, 5}, {11, 5}},
+		Portals: []portalDef{{A: cell{2, 2}, B: cell{17, 17}}},
+		Movers: []moverDef{
+			{Kind: "patrol", Path: []cell{{3, 14}, {16, 14}}, Every: 2},
+			{Kind: "bouncer", Pos: cell{15, 3}, Dir: cell{-1, 1}},
+		},
+	}
+	garden := &Map{Name: "Garden", Width: 16, Height: 12, FoodZones: []cell{{2, 2}, {7, 2}, {13, 2}, {2, 9}, {7, 9}, {13, 9}}}
+	tests := []struct {
+		name       string
+		mode       string
+		difficulty string
+		board      *Map
+		moves      int
+		settings   func(g *Game) // choices changed in settings mid-run, before saving
+	}{
+		{"classic on an open board", "Classic", "Normal", nil, 40, nil},
+		{"time attack", "Time Attack 1:00", "Easy", nil, 60, nil},
+		{"survival with hazards", "Survival", "Hard", nil, 80, nil},
+		{"practice", "Practice", "Normal", nil, 25, nil},
+		{"enemies and portals", "Classic", "Insane", arena, 20, nil},
+		{"food zone", "Classic", "Normal", garden, 30, nil},
+		{"straight after the start", "Classic", "Normal", nil, 0, nil},
+		{"settings changed mid-run", "Classic", "Hard", arena, 20, func(g *Game) {
+			g.cycleMap(1)
+			g.cycleDifficulty(1)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inTempDir(t)
+			played := savedGameTestbed(tt.board)
+			played.Mode = modeByName(tt.mode)
+			played.Map = tt.board
+			played.setDifficulty(difficultyByName(builtinDifficulties, tt.difficulty))
+			played.resetRun(99)
+			playMoves(t, played, tt.moves)
+			if played.State.GameOver || played.State.TimeUp {
+				t.Fatalf("run ended after %d moves, before it could be saved", played.State.Ticks)
+			}
+			if tt.settings != nil {
+				tt.settings(played)
+			}
+			played.saveGame()
+
+			data, err := os.ReadFile(saveFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var saved SavedGame
+			if err := json.Unmarshal(data, &saved); err != nil {
+				t.Fatal(err)
+			}
+			continued := savedGameTestbed(tt.board)
+			if err := continued.restoreSavedGame(&saved); err != nil {
+				t.Fatal(err)
+			}
+			if continued.Mode.Name != tt.mode || continued.Difficulty.Name != tt.difficulty || continued.Map != played.Map {
+				t.Errorf("continued as %s/%s on %v, want %s/%s on %v",
+					continued.Mode.Name, continued.Difficulty.Name, continued.Map, tt.mode, tt.difficulty, played.Map)
+			}
+			if continued.rngSource.drawn != played.rngSource.drawn {
+				t.Errorf("random generator at %d draws, want %d", continued.rngSource.drawn, played.rngSource.drawn)
+			}
+			if !slices.Equal(continued.Inputs.Events, played.Inputs.Events) {
+				t.Errorf("inputs = %v, want %v", continued.Inputs.Events, played.Inputs.Events)
+			}
+			sameRun(t, "on continue", continued, played)
+
+			// Both play on identically, random food and hazards included.
+			playMoves(t, played, 40)
+			playMoves(t, continued, 40)
+			sameRun(t, "40 moves later", continued, played)
+		})
+	}
+}
+
+// inTempDir runs the rest of the test in an empty folder, where the game writes its
+// save and settings files.
+func inTempDir(t *testing.T) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+// savedGameTestbed returns a headless game that can save and continue runs, with
+// board installed as a map.
+func savedGameTestbed(board *Map) *Game {
+	g := newHeadlessGame(20, 20, Modes[0], nil)
+	g.Difficulties = builtinDifficulties
+	g.Settings = &SettingsManager{path: settingsFile}
+	if board != nil {
+		g.Maps = []*Map{board}
+	}
+	return g
+}
+
+// playMoves steers the snake towards the apple for up to n moves, stopping early if
+// the run ends.
+func playMoves(t *testing.T, g *Game, n int) {
+	t.Helper()
+	for range n {
+		if g.State.GameOver || g.State.TimeUp {
+			return
+		}
+		steerToApple(g)
+		g.tick()
+	}
+}
+
+// sameRun fails the test if two games are not in the same state.
+func sameRun(t *testing.T, when string, got, want *Game) {
+	t.Helper()
+	a, err := json.Marshal(got.captureRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(want.captureRun())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(a) != string(b) {
+		t.Errorf("%s the runs differ:\n%s\n%s", when, a, b)
+	}
+	if got.State.GameOver != want.State.GameOver {
+		t.Errorf("%s game over = %v, want %v", when, got.State.GameOver, want.State.GameOver)
+	}
+}
+
+func TestSaveRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(s *SavedGame)
+	}{
+		{"older version", func(s *SavedGame) { s.Version = 1 }},
+		{"unknown mode", func(s *SavedGame) { s.Mode = "Snake Racing" }},
+		{"unknown difficulty", func(s *SavedGame) { s.Difficulty = "Impossible" }},
+		{"no board", func(s *SavedGame) { s.Run.GridWidth = 0 }},
+		{"no snake", func(s *SavedGame) { s.Run.Snake.Segments = s.Run.Snake.Segments[:1] }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inTempDir(t)
+			g := savedGameTestbed(nil)
+			g.resetRun(5)
+			saved := SavedGame{Version: saveVersion, Mode: g.Mode.Name, Difficulty: g.Difficulty.Name, Run: *g.captureRun()}
+			tt.change(&saved)
+			if err := savedGameTestbed(nil).restoreSavedGame(&saved); err == nil {
+				t.Error("continued, want an error")
+			}
+		})
+	}
+}
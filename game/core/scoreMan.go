@@ -0,0 +1,121 @@
+package core
+
+import "snakeGame/game/render"
+
+// ScoringRule configures how points are awarded in a mode. Zero values turn the
+// corresponding bonus off.
+type ScoringRule struct {
+	ComboWindow    int // moves after a bite in which the next bite raises the combo
+	MaxCombo       int // highest combo multiplier
+	QuickMoves     int // food eaten within this many moves of spawning earns QuickBonus
+	QuickBonus     int
+	MilestoneEvery int // every time the length reaches a multiple of this, earn MilestoneBonus
+	MilestoneBonus int
+}
+
+// ScoreBreakdown splits a run's score by where the points came from.
+type ScoreBreakdown struct {
+	Food      int // base points of the food eaten
+	Combo     int // extra from the combo multiplier
+	Quick     int // bonuses for eating food soon after it spawned
+	Milestone int // bonuses for length milestones
+	PowerUp   int // extra from the score multiplier power-up
+}
+
+// ScoreManager turns bites into points according to a ScoringRule and keeps the
+// breakdown for the game-over screen.
+type ScoreManager struct {
+	Rule      ScoringRule
+	Combo     int // current combo multiplier; 1 = no combo
+	Breakdown ScoreBreakdown
+	sinceBite int // moves since the last bite
+	milestone int // milestones reached so far
+}
+
+// NewScoreManager creates a score manager for the given rule with no combo running.
+func NewScoreManager(rule ScoringRule) *ScoreManager {
+	s := &ScoreManager{Rule: rule}
+	s.Reset()
+	return s
+}
+
+// Tick counts one move and ends the combo once its window has passed.
+func (s *ScoreManager) Tick() {
+	s.sinceBite++
+	if s.Combo > 1 && s.sinceBite > s.Rule.ComboWindow {
+		s.Combo = 1
+	}
+}
+
+// Bite scores eating food worth base points that has been on the board for age
+// moves, leaving the snake length long. powerUp multiplies everything but the
+// milestone bonus. It returns the points earned.
+func (s *ScoreManager) Bite(base, age, length, powerUp int) int {
+	if s.Rule.ComboWindow > 0 && s.sinceBite <= s.Rule.ComboWindow && s.Combo < s.Rule.MaxCombo {
+		s.Combo++
+	}
+	s.sinceBite = 0
+
+	combo := base * (s.Combo - 1)
+	quick := 0
+	if s.Rule.QuickMoves > 0 && age <= s.Rule.QuickMoves {
+		quick = s.Rule.QuickBonus
+	}
+	subtotal := base + combo + quick
+	extra := subtotal * (powerUp - 1)
+
+	milestone := 0
+	if s.Rule.MilestoneEvery > 0 {
+		for length/s.Rule.MilestoneEvery > s.milestone {
+			s.milestone++
+			milestone += s.Rule.MilestoneBonus
+		}
+	}
+
+	s.Breakdown.Food += base
+	s.Breakdown.Combo += combo
+	s.Breakdown.Quick += quick
+	s.Breakdown.PowerUp += extra
+	s.Breakdown.Milestone += milestone
+	return subtotal + extra + milestone
+}
+
+// Reset starts a new run.
+func (s *ScoreManager) Reset() {
+	s.Combo = 1
+	s.Breakdown = ScoreBreakdown{}
+	// Outside the window, so the first bite of a run never starts a combo.
+	s.sinceBite = s.Rule.ComboWindow + 1
+	s.milestone = 0
+}
+
+// ComboTimer returns the running combo as a HUD timer, or false if there is none.
+func (s *ScoreManager) ComboTimer() (render.HUDTimer, bool) {
+	if s.Combo <= 1 {
+		return render.HUDTimer{}, false
+	}
+	left := s.Rule.ComboWindow - s.sinceBite
+	return render.HUDTimer{
+		Label:     "Combo",
+		Stacks:    s.Combo,
+		Remaining: float64(left) / float64(s.Rule.ComboWindow),
+	}, true
+}
+
+// Lines lists the non-zero parts of the breakdown for the game-over screen.
+func (b ScoreBreakdown) Lines() []render.ScoreLine {
+	all := []render.ScoreLine{
+		{Label: "Food", Points: b.Food},
+		{Label: "Combo", Points: b.Combo},
+		{Label: "Quick", Points: b.Quick},
+		{Label: "Length", Points: b.Milestone},
+		{Label: "Power-up", Points: b.PowerUp},
+	}
+	lines := all[:0]
+	for _, l := range all {
+		if l.Points != 0 {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
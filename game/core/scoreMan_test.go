@@ -0,0 +1,99 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+// bite is food eaten after waiting some moves; a negative wait starts a new run instead.
+type bite struct {
+	wait                       int
+	base, age, length, powerUp int
+}
+
+// apple is a plain bite: one point, not quick, no milestone, no power-up.
+func apple(wait int) bite {
+	return bite{wait: wait, base: 1, age: 10, length: 4, powerUp: 1}
+}
+
+func TestScoreManager(t *testing.T) {
+	rule := ScoringRule{ComboWindow: 5, MaxCombo: 3, QuickMoves: 3, QuickBonus: 2, MilestoneEvery: 10, MilestoneBonus: 5}
+	tests := []struct {
+		name      string
+		rule      ScoringRule
+		bites     []bite
+		points    []int
+		combo     int
+		breakdown ScoreBreakdown
+	}{
+		{"first bite of a run", rule, []bite{apple(0)}, []int{1}, 1, ScoreBreakdown{Food: 1}},
+		{"bites in the window raise the combo", rule, []bite{apple(0), apple(2), apple(1), apple(1)},
+			[]int{1, 2, 3, 3}, 3, ScoreBreakdown{Food: 4, Combo: 5}},
+		{"last move of the window", rule, []bite{apple(0), apple(5)}, []int{1, 2}, 2, ScoreBreakdown{Food: 2, Combo: 1}},
+		{"combo ends after the window", rule, []bite{apple(0), apple(1), apple(6)},
+			[]int{1, 2, 1}, 1, ScoreBreakdown{Food: 3, Combo: 1}},
+		{"golden apple in a combo", rule, []bite{apple(0), {wait: 1, base: 5, age: 10, length: 4, powerUp: 1}},
+			[]int{1, 10}, 2, ScoreBreakdown{Food: 6, Combo: 5}},
+		{"quick bites", rule, []bite{{base: 1, age: 3, length: 4, powerUp: 1}, {wait: 10, base: 1, age: 4, length: 4, powerUp: 1}},
+			[]int{3, 1}, 1, ScoreBreakdown{Food: 2, Quick: 2}},
+		{"length milestones", rule, []bite{
+			{base: 1, age: 10, length: 10, powerUp: 1},
+			{wait: 10, base: 1, age: 10, length: 11, powerUp: 1},
+			{wait: 10, base: 1, age: 10, length: 30, powerUp: 1},
+		}, []int{6, 1, 11}, 1, ScoreBreakdown{Food: 3, Milestone: 15}},
+		{"score power-up", rule, []bite{
+			{base: 1, age: 10, length: 4, powerUp: 2},
+			{wait: 1, base: 1, age: 10, length: 4, powerUp: 3},
+			{wait: 10, base: 1, age: 10, length: 10, powerUp: 2},
+		}, []int{2, 6, 7}, 1, ScoreBreakdown{Food: 3, Combo: 1, PowerUp: 6, Milestone: 5}},
+		{"new run after a combo", rule, []bite{apple(0), apple(1), {wait: -1}, apple(0)},
+			[]int{1, 2, 1}, 1, ScoreBreakdown{Food: 1}},
+		{"no bonuses", ScoringRule{}, []bite{apple(0), apple(1), {base: 1, age: 0, length: 40, powerUp: 1}},
+			[]int{1, 1, 1}, 1, ScoreBreakdown{Food: 3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScoreManager(tt.rule)
+			var points []int
+			for _, b := range tt.bites {
+				if b.wait < 0 {
+					s.Reset()
+					continue
+				}
+				for range b.wait {
+					s.Tick()
+				}
+				points = append(points, s.Bite(b.base, b.age, b.length, b.powerUp))
+			}
+			if !slices.Equal(points, tt.points) {
+				t.Errorf("points = %v, want %v", points, tt.points)
+			}
+			if s.Combo != tt.combo {
+				t.Errorf("combo = %d, want %d", s.Combo, tt.combo)
+			}
+			if s.Breakdown != tt.breakdown {
+				t.Errorf("breakdown = %+v, want %+v", s.Breakdown, tt.breakdown)
+			}
+			if _, ok := s.ComboTimer(); ok != (tt.combo > 1) {
+				t.Errorf("combo timer shown = %v with combo %d", ok, tt.combo)
+			}
+		})
+	}
+}
+
+func TestComboTimer(t *testing.T) {
+	s := NewScoreManager(ScoringRule{ComboWindow: 4, MaxCombo: 5})
+	s.Bite(1, 10, 4, 1)
+	s.Tick()
+	s.Bite(1, 10, 4, 1)
+	for moves, want := range []float64{1, 0.75, 0.5, 0.25, 0} {
+		timer, ok := s.ComboTimer()
+		if !ok || timer.Stacks != 2 || timer.Remaining != want {
+			t.Fatalf("after %d moves: timer %+v (shown %v), want x2 with %v left", moves, timer, ok, want)
+		}
+		s.Tick()
+	}
+	if _, ok := s.ComboTimer(); ok {
+		t.Error("combo timer still shown after the window")
+	}
+}
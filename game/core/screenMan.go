@@ -0,0 +1,231 @@
+package core
+
+import (
+	"snakeGame/game/render"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// GameScreen identifies a screen/state of the game.
+type GameScreen int
+
+const (
+	ScreenTitle GameScreen = iota
+	ScreenPlaying
+	ScreenPaused
+	ScreenCountdown
+	ScreenSettings
+	ScreenGameOver
+	ScreenHighScores
+	ScreenEditor
+	ScreenEditorMenu
+	ScreenStatistics
+)
+
+// Screen is one state of the game. Screens live on a stack: only the top screen
+// receives Update, while Draw starts from the lowest screen that the overlays above
+// it still show through.
+type Screen interface {
+	ID() GameScreen
+	Enter() // called when the screen becomes the top of the stack
+	Exit()  // called when the screen stops being the top of the stack
+	Update() error
+	Draw(screen *ebiten.Image)
+	Overlay() bool // true if screens below should be drawn first
+}
+
+// baseScreen provides no-op hooks for screens that don't need them.
+type baseScreen struct{}
+
+func (baseScreen) Enter()        {}
+func (baseScreen) Exit()         {}
+func (baseScreen) Overlay() bool { return false }
+
+// Transition is the visual effect used when the top screen changes.
+type Transition int
+
+const (
+	TransitionNone Transition = iota
+	TransitionFade
+	TransitionSlide
+)
+
+// transitionFrames is how long fade and slide transitions last.
+const transitionFrames = 15
+
+// ScreenManager owns the screen stack and animates transitions between screens.
+type ScreenManager struct {
+	stack []Screen
+	ui    *render.UIManager // queues the UI text, which is not part of the frame images
+
+	kind      Transition
+	frame     int           // frames elapsed in the running transition
+	lastFrame *ebiten.Image // copy of the most recent frame, the "from" image of a transition
+	fromFrame *ebiten.Image
+	toFrame   *ebiten.Image
+	lastText  render.TextFrame // text of the most recent frame
+	fromText  render.TextFrame
+}
+
+// NewScreenManager creates an empty screen stack whose screens draw text with ui.
+func NewScreenManager(ui *render.UIManager) *ScreenManager {
+	return &ScreenManager{ui: ui}
+}
+
+// Top returns the screen on top of the stack, or nil if it is empty.
+func (sm *ScreenManager) Top() Screen {
+	if len(sm.stack) == 0 {
+		return nil
+	}
+	return sm.stack[len(sm.stack)-1]
+}
+
+// Current returns the ID of the top screen.
+func (sm *ScreenManager) Current() GameScreen {
+	if top := sm.Top(); top != nil {
+		return top.ID()
+	}
+	return ScreenTitle
+}
+
+// Contains reports whether a screen with the given ID is anywhere on the stack.
+func (sm *ScreenManager) Contains(id GameScreen) bool {
+	for _, s := range sm.stack {
+		if s.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Push puts s on top of the stack, e.g. an overlay over gameplay.
+func (sm *ScreenManager) Push(s Screen, t Transition) {
+	sm.beginTransition(t)
+	if top := sm.Top(); top != nil {
+		top.Exit()
+	}
+	sm.stack = append(sm.stack, s)
+	s.Enter()
+}
+
+// Pop removes the top screen and returns to the one below it.
+func (sm *ScreenManager) Pop(t Transition) {
+	top := sm.Top()
+	if top == nil {
+		return
+	}
+	sm.beginTransition(t)
+	top.Exit()
+	sm.stack = sm.stack[:len(sm.stack)-1]
+	if next := sm.Top(); next != nil {
+		next.Enter()
+	}
+}
+
+// Replace swaps the top screen for s without re-entering the screen below it.
+func (sm *ScreenManager) Replace(s Screen, t Transition) {
+	sm.beginTransition(t)
+	if top := sm.Top(); top != nil {
+		top.Exit()
+		sm.stack = sm.stack[:len(sm.stack)-1]
+	}
+	sm.stack = append(sm.stack, s)
+	s.Enter()
+}
+
+// Switch clears the whole stack and shows s on its own.
+func (sm *ScreenManager) Switch(s Screen, t Transition) {
+	sm.beginTransition(t)
+	if top := sm.Top(); top != nil {
+		top.Exit()
+	}
+	sm.stack = []Screen{s}
+	s.Enter()
+}
+
+// beginTransition freezes the last drawn frame as the image to transition away from.
+func (sm *ScreenManager) beginTransition(t Transition) {
+	if t == TransitionNone || sm.lastFrame == nil {
+		sm.kind = TransitionNone
+		return
+	}
+	if sm.fromFrame == nil || sm.fromFrame.Bounds() != sm.lastFrame.Bounds() {
+		sm.fromFrame = ebiten.NewImage(sm.lastFrame.Bounds().Dx(), sm.lastFrame.Bounds().Dy())
+	}
+	sm.fromFrame.Clear()
+	sm.fromFrame.DrawImage(sm.lastFrame, nil)
+	sm.fromText = sm.lastText
+	sm.kind = t
+	sm.frame = 0
+}
+
+// Update forwards to the top screen. Input is ignored while a transition plays.
+func (sm *ScreenManager) Update() error {
+	if sm.kind != TransitionNone {
+		sm.frame++
+		if sm.frame >= transitionFrames {
+			sm.kind = TransitionNone
+		}
+		return nil
+	}
+	if top := sm.Top(); top != nil {
+		return top.Update()
+	}
+	return nil
+}
+
+// Draw renders the visible part of the stack, blending in any running transition.
+// The frame buffers follow the screen when a board of another size is shown. The
+// queued text of both frames moves and fades along with them.
+func (sm *ScreenManager) Draw(screen *ebiten.Image) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if sm.lastFrame == nil || sm.lastFrame.Bounds() != screen.Bounds() {
+		sm.lastFrame = ebiten.NewImage(w, h)
+		sm.toFrame = ebiten.NewImage(w, h)
+	}
+
+	target := screen
+	if sm.kind != TransitionNone {
+		sm.toFrame.Clear()
+		target = sm.toFrame
+	}
+	sm.drawStack(target)
+
+	if sm.kind != TransitionNone {
+		p := float64(sm.frame) / transitionFrames
+		from := &ebiten.DrawImageOptions{}
+		to := &ebiten.DrawImageOptions{}
+		switch sm.kind {
+		case TransitionFade:
+			to.ColorScale.ScaleAlpha(float32(p))
+		case TransitionSlide:
+			from.GeoM.Translate(-float64(w)*p, 0)
+			to.GeoM.Translate(float64(w)*(1-p), 0)
+		}
+		screen.DrawImage(sm.fromFrame, from)
+		screen.DrawImage(sm.toFrame, to)
+
+		toText := sm.ui.TakeText()
+		fromAlpha, toAlpha := float32(1), float32(1)
+		if sm.kind == TransitionFade {
+			fromAlpha, toAlpha = float32(1-p), float32(p)
+		}
+		sm.ui.QueueText(sm.fromText, from.GeoM, fromAlpha)
+		sm.ui.QueueText(toText, to.GeoM, toAlpha)
+	}
+
+	sm.lastFrame.Clear()
+	sm.lastFrame.DrawImage(screen, nil)
+	sm.lastText = sm.ui.Text()
+}
+
+// drawStack draws from the lowest visible screen up to the top.
+func (sm *ScreenManager) drawStack(screen *ebiten.Image) {
+	start := len(sm.stack) - 1
+	for start > 0 && sm.stack[start].Overlay() {
+		start--
+	}
+	for i := start; i >= 0 && i < len(sm.stack); i++ {
+		sm.stack[i].Draw(screen)
+	}
+}
@@ -0,0 +1,267 @@
+package core
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// titleScreen shows the main menu.
+type titleScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *titleScreen) ID() GameScreen { return ScreenTitle }
+
+// Enter drops any daily challenge or editor test run, so the title always offers
+// the mode and map chosen in settings.
+func (s *titleScreen) Enter() {
+	s.g.SoundMan.PauseLoopingSound("bgm")
+	s.g.leaveDaily()
+	s.g.leaveTestPlay()
+	s.g.setGridSize(s.g.runGridSize())
+}
+
+func (s *titleScreen) Update() error {
+	s.g.titleMenu.Update()
+	return nil
+}
+
+func (s *titleScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.g.Theme.Palette.Background)
+	s.g.UI.DrawTitleScreen(screen, s.g.screenWidth, s.g.screenHeight, s.g.titleMenu)
+}
+
+// playingScreen runs the game itself.
+type playingScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *playingScreen) ID() GameScreen { return ScreenPlaying }
+
+// Enter resumes the music whenever gameplay regains focus, e.g. after unpausing,
+// and restarts the simulation clock so time spent away is not played back.
+func (s *playingScreen) Enter() {
+	s.g.Speed.ResetClock()
+	if !s.g.State.GameOver {
+		s.g.SoundMan.PlayLoopingSound("bgm")
+	}
+}
+
+func (s *playingScreen) Update() error {
+	return s.g.updatePlaying()
+}
+
+func (s *playingScreen) Draw(screen *ebiten.Image) {
+	s.g.drawBoard(screen)
+
+	// Draw particles, floating text and flashes on top of the board.
+	s.g.Effects.Draw(screen)
+
+	s.g.drawHUD(screen)
+}
+
+// pausedScreen is an overlay with the pause menu that freezes gameplay underneath it.
+type pausedScreen struct {
+	g *Game
+}
+
+func (s *pausedScreen) ID() GameScreen { return ScreenPaused }
+func (s *pausedScreen) Overlay() bool  { return true }
+func (s *pausedScreen) Exit()          {}
+
+func (s *pausedScreen) Enter() {
+	s.g.State.SetPaused(true)
+	s.g.SoundMan.PauseLoopingSound("bgm")
+}
+
+func (s *pausedScreen) Update() error {
+	if s.g.pausePressed() {
+		s.g.resume()
+		return nil
+	}
+	s.g.pauseMenu.Update()
+	return nil
+}
+
+func (s *pausedScreen) Draw(screen *ebiten.Image) {
+	s.g.UI.DrawDim(screen)
+	s.g.UI.DrawPauseMenu(screen, s.g.screenWidth, s.g.screenHeight, s.g.pauseMenu)
+}
+
+// countdownScreen is an overlay that counts 3-2-1 before gameplay resumes.
+type countdownScreen struct {
+	g      *Game
+	frames int // frames left until play resumes
+}
+
+func (s *countdownScreen) ID() GameScreen { return ScreenCountdown }
+func (s *countdownScreen) Overlay() bool  { return true }
+
+func (s *countdownScreen) Enter() {
+	s.frames = countdownSeconds * ebiten.TPS()
+}
+
+// Exit unfreezes gameplay once the count reaches zero.
+func (s *countdownScreen) Exit() {
+	s.g.State.SetPaused(false)
+}
+
+func (s *countdownScreen) Update() error {
+	// Pausing again during the countdown goes straight back to the menu.
+	if s.g.pausePressed() {
+		s.g.Screens.Replace(&pausedScreen{g: s.g}, TransitionNone)
+		return nil
+	}
+	s.frames--
+	if s.frames <= 0 {
+		s.g.Screens.Pop(TransitionNone)
+	}
+	return nil
+}
+
+func (s *countdownScreen) Draw(screen *ebiten.Image) {
+	remaining := (s.frames + ebiten.TPS() - 1) / ebiten.TPS()
+	s.g.UI.DrawCountdown(screen, s.g.screenWidth, s.g.screenHeight, remaining)
+}
+
+// settingsScreen shows the settings menu.
+type settingsScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *settingsScreen) ID() GameScreen { return ScreenSettings }
+
+func (s *settingsScreen) Enter() {
+	s.g.settingsMenu.Reset()
+}
+
+func (s *settingsScreen) Update() error {
+	s.g.settingsMenu.Update()
+	return nil
+}
+
+func (s *settingsScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.g.Theme.Palette.Background)
+	s.g.UI.DrawSettingsScreen(screen, s.g.screenWidth, s.g.screenHeight, s.g.settingsMenu)
+}
+
+// gameOverScreen is an overlay shown over the frozen final board.
+type gameOverScreen struct {
+	g *Game
+}
+
+func (s *gameOverScreen) ID() GameScreen { return ScreenGameOver }
+func (s *gameOverScreen) Overlay() bool  { return true }
+func (s *gameOverScreen) Exit()          {}
+
+func (s *gameOverScreen) Enter() {
+	s.g.SoundMan.PauseLoopingSound("bgm")
+	s.g.gameOverMenu.Title = "GAME OVER"
+	if s.g.State.TimeUp {
+		s.g.gameOverMenu.Title = "TIME'S UP"
+	}
+	s.g.gameOverMenu.Reset()
+}
+
+func (s *gameOverScreen) Update() error {
+	s.g.gameOverMenu.Update()
+	return nil
+}
+
+func (s *gameOverScreen) Draw(screen *ebiten.Image) {
+	s.g.UI.DrawDim(screen)
+	s.g.UI.DrawGameOverOverlay(screen, s.g.screenWidth, s.g.screenHeight, s.g.gameOverMenu, s.g.runStats())
+}
+
+// highScoresScreen lists the best scores recorded in the profile.
+type highScoresScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *highScoresScreen) ID() GameScreen { return ScreenHighScores }
+
+func (s *highScoresScreen) Update() error {
+	s.g.highScoresMenu.Update()
+	return nil
+}
+
+func (s *highScoresScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.g.Theme.Palette.Background)
+	s.g.UI.DrawHighScores(screen, s.g.screenWidth, s.g.screenHeight, s.g.highScoresMenu, s.g.Profile.ScoresFor(s.g.Mode.ScoreTable))
+}
+
+// statisticsScreen shows the lifetime statistics from the profile.
+type statisticsScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *statisticsScreen) ID() GameScreen { return ScreenStatistics }
+
+func (s *statisticsScreen) Update() error {
+	s.g.statsMenu.Update()
+	return nil
+}
+
+func (s *statisticsScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.g.Theme.Palette.Background)
+	s.g.UI.DrawStatistics(screen, s.g.screenWidth, s.g.screenHeight, s.g.statsMenu, s.g.statsInfo(), s.g.statsHeatmapView())
+}
+
+// editorScreen is the level editor, where maps are painted cell by cell.
+type editorScreen struct {
+	baseScreen
+	g *Game
+}
+
+func (s *editorScreen) ID() GameScreen { return ScreenEditor }
+
+// Enter sizes the screen to the map being edited, which may differ from the board
+// of the last run.
+func (s *editorScreen) Enter() {
+	s.g.SoundMan.PauseLoopingSound("bgm")
+	s.g.setGridSize(s.g.Editor.Map.Width, s.g.Editor.Map.Height)
+}
+
+func (s *editorScreen) Update() error {
+	s.g.updateEditor()
+	return nil
+}
+
+func (s *editorScreen) Draw(screen *ebiten.Image) {
+	screen.Fill(s.g.Theme.Palette.Background)
+	s.g.drawEditor(screen)
+}
+
+// editorMenuScreen is an overlay over the editor with the map's details, test
+// play, saving and loading.
+type editorMenuScreen struct {
+	g *Game
+}
+
+func (s *editorMenuScreen) ID() GameScreen { return ScreenEditorMenu }
+func (s *editorMenuScreen) Overlay() bool  { return true }
+func (s *editorMenuScreen) Enter()         {}
+
+// Exit stops any typing left unfinished.
+func (s *editorMenuScreen) Exit() {
+	s.g.Editor.typing = nil
+}
+
+// Update sends keys to the field being typed into, if any, instead of the menu.
+func (s *editorMenuScreen) Update() error {
+	if s.g.Editor.typing != nil {
+		s.g.Editor.updateTyping()
+		return nil
+	}
+	s.g.editorMenu.Update()
+	return nil
+}
+
+func (s *editorMenuScreen) Draw(screen *ebiten.Image) {
+	s.g.UI.DrawDim(screen)
+	s.g.UI.DrawEditorMenu(screen, s.g.screenWidth, s.g.screenHeight, s.g.editorMenu)
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+)
+
+const settingsFile = "settings.json"
+
+// SettingsManager holds player preferences and persists them between sessions.
+type SettingsManager struct {
+	Skin       string  `json:"skin"`
+	Theme      string  `json:"theme"`
+	Smooth     bool    `json:"smooth"`     // interpolate snake movement between ticks
+	Effects    bool    `json:"effects"`    // particles, screen shake and flashes
+	Volume     float64 `json:"volume"`     // master volume from 0 to 1
+	Difficulty string  `json:"difficulty"` // name of the difficulty preset
+	Mode       string  `json:"mode"`       // name of the game mode
+	Map        string  `json:"map"`        // name of the board layout; "" = open board
+	Ghost      bool    `json:"ghost"`      // race the personal best, replaying its board
+	path       string
+}
+
+// NewSettingsManager loads saved settings, falling back to defaults if none exist.
+func NewSettingsManager() *SettingsManager {
+	s := &SettingsManager{
+		Skin:       "Yellow",
+		Theme:      "Garden",
+		Smooth:     true,
+		Effects:    true,
+		Volume:     1,
+		Difficulty: "Normal",
+		Mode:       "Classic",
+		path:       settingsFile,
+	}
+	s.Load()
+	return s
+}
+
+// Load reads settings from disk. Missing or invalid files leave the defaults in place.
+func (s *SettingsManager) Load() {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		log.Printf("Failed to parse settings: %v", err)
+	}
+}
+
+// Save writes the current settings to disk.
+func (s *SettingsManager) Save() {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		log.Printf("Failed to encode settings: %v", err)
+		return
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		log.Printf("Failed to save settings: %v", err)
+	}
+}
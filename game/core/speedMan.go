@@ -1,39 +1,143 @@
 package core
 
-import "math"
+import (
+	"time"
+)
 
-// SpeedManager controls how often the game updates the snake's position based on level.
+// Fixed-timestep limits that keep a long stall (window drag, breakpoint) from
+// fast-forwarding the snake across the board.
+const (
+	maxFrameTime    = 250 * time.Millisecond // longest real-time gap counted in one update
+	maxStepsPerCall = 4                      // most moves simulated in one update
+)
+
+// SpeedManager runs the simulation on a fixed timestep. Real time is collected in an
+// accumulator and spent in whole moves, so the snake's speed is defined in moves per
+// second regardless of Ebiten's TPS or the monitor's refresh rate.
 type SpeedManager struct {
-	FrameCount int
-	FrameDelay int
+	Interval    time.Duration    // time between moves
+	Curve       SpeedCurve       // level-to-speed curve from the selected difficulty
+	Now         func() time.Time // clock, replaceable for replays
+	accumulator time.Duration    // real time not yet spent on moves
+	lastUpdate  time.Time        // when Advance last ran; zero after a reset
+	level       int              // level the interval was last computed for
+	modifiers   map[string]*speedModifier
+}
+
+// speedModifier is a temporary speed multiplier from one source, e.g. food or a power-up.
+type speedModifier struct {
+	factor float64
+	moves  int // moves left before it wears off
 }
 
-// NewSpeedManager initializes the manager with default delay.
+// NewSpeedManager initializes the manager with the default speed.
 func NewSpeedManager() *SpeedManager {
 	return &SpeedManager{
-		FrameCount: 0,
-		FrameDelay: 20, // default speed (3 moves/sec)
+		Interval: time.Second / 3, // default speed (3 moves/sec)
+		Now:      time.Now,
 	}
 }
 
-// ShouldUpdate returns true if it's time to update the game logic (snake move).
-func (s *SpeedManager) ShouldUpdate() bool {
-	s.FrameCount++
-	if s.FrameCount >= s.FrameDelay {
-		s.FrameCount = 0
-		return true
+// Advance measures the real time since the previous call and returns how many
+// moves are due along with the time that passed.
+func (s *SpeedManager) Advance() (int, time.Duration) {
+	now := s.Now()
+	if s.lastUpdate.IsZero() {
+		s.lastUpdate = now
+		return 0, 0
+	}
+	dt := now.Sub(s.lastUpdate)
+	s.lastUpdate = now
+	if dt > maxFrameTime {
+		dt = maxFrameTime
 	}
-	return false
+	return s.Step(dt), dt
 }
 
-// AdjustDelayByLevel updates delay based on level to increase game speed.
-func (s *SpeedManager) AdjustDelayByLevel(level int) {
-	// Simple formula: base delay - (level * 2), but not lower than 5 frames
-	s.FrameDelay = int(math.Max(5, float64(20 - level*2)))
+// Step adds dt to the accumulator and returns how many moves are due.
+func (s *SpeedManager) Step(dt time.Duration) int {
+	s.accumulator += dt
+	steps := 0
+	for s.accumulator >= s.Interval && steps < maxStepsPerCall {
+		s.accumulator -= s.Interval
+		steps++
+	}
+	// Drop any backlog beyond the cap rather than catching up later.
+	if steps == maxStepsPerCall {
+		s.accumulator %= s.Interval
+	}
+	return steps
 }
 
-// ResetFrameCount clears the frame counter, useful on restart.
-func (s *SpeedManager) ResetFrameCount() {
-	s.FrameCount = 0
+// AdjustSpeedByLevel updates the move interval from the difficulty's speed curve
+// and any active modifiers.
+func (s *SpeedManager) AdjustSpeedByLevel(level int) {
+	s.level = level
+	mps := s.Curve.MovesPerSecond(level)
+	for _, m := range s.modifiers {
+		mps *= m.factor
+	}
+	s.Interval = time.Duration(float64(time.Second) / mps)
 }
 
+// SetModifier multiplies the speed by factor for the given number of moves. Each
+// source has one modifier at a time; setting it again replaces the old one.
+// Modifiers from different sources multiply together.
+func (s *SpeedManager) SetModifier(source string, factor float64, moves int) {
+	if s.modifiers == nil {
+		s.modifiers = make(map[string]*speedModifier)
+	}
+	s.modifiers[source] = &speedModifier{factor: factor, moves: moves}
+	s.AdjustSpeedByLevel(s.level)
+}
+
+// TickModifiers counts down active modifiers by one move and restores the
+// speed of any that run out.
+func (s *SpeedManager) TickModifiers() {
+	changed := false
+	for source, m := range s.modifiers {
+		m.moves--
+		if m.moves <= 0 {
+			delete(s.modifiers, source)
+			changed = true
+		}
+	}
+	if changed {
+		s.AdjustSpeedByLevel(s.level)
+	}
+}
+
+// ClearModifiers removes all temporary speed modifiers.
+func (s *SpeedManager) ClearModifiers() {
+	s.modifiers = nil
+	s.AdjustSpeedByLevel(s.level)
+}
+
+// MovesPerSecond returns the current speed.
+func (s *SpeedManager) MovesPerSecond() float64 {
+	return float64(time.Second) / float64(s.Interval)
+}
+
+// Progress returns how far the game is towards the next move, from 0 up to (but not including) 1.
+func (s *SpeedManager) Progress() float64 {
+	if s.Interval <= 0 {
+		return 0
+	}
+	p := float64(s.accumulator) / float64(s.Interval)
+	if p >= 1 {
+		p = 0.999
+	}
+	return p
+}
+
+// ResetClock forgets the last update time so that time spent away from gameplay
+// (paused, in menus) is not simulated when play resumes.
+func (s *SpeedManager) ResetClock() {
+	s.lastUpdate = time.Time{}
+}
+
+// Reset clears the accumulator and clock, useful on restart.
+func (s *SpeedManager) Reset() {
+	s.accumulator = 0
+	s.ResetClock()
+}
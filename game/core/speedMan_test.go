@@ -0,0 +1,92 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSpeedStep(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name     string
+		interval time.Duration
+		frames   []time.Duration
+		steps    []int
+		progress float64 // towards the next move after the last frame
+	}{
+		{"frames shorter than a move", 100 * ms, []time.Duration{40 * ms, 40 * ms, 40 * ms}, []int{0, 0, 1}, 0.2},
+		{"exactly one move", 100 * ms, []time.Duration{100 * ms}, []int{1}, 0},
+		{"two moves in one frame", 100 * ms, []time.Duration{250 * ms}, []int{2}, 0.5},
+		{"50 fps at 5 moves a second", 200 * ms, slices.Repeat([]time.Duration{20 * ms}, 20), everyTenth(20), 0},
+		{"capped backlog is dropped", 10 * ms, []time.Duration{75 * ms, 4 * ms}, []int{maxStepsPerCall, 0}, 0.9},
+		{"nothing passed", 100 * ms, []time.Duration{0}, []int{0}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSpeedManager()
+			s.Interval = tt.interval
+			var steps []int
+			for _, dt := range tt.frames {
+				steps = append(steps, s.Step(dt))
+			}
+			if !slices.Equal(steps, tt.steps) {
+				t.Errorf("steps = %v, want %v", steps, tt.steps)
+			}
+			if got := s.Progress(); math.Abs(got-tt.progress) > 0.02 {
+				t.Errorf("progress = %v, want %v", got, tt.progress)
+			}
+		})
+	}
+}
+
+func TestSpeedAdvance(t *testing.T) {
+	ms := time.Millisecond
+	tests := []struct {
+		name   string
+		gaps   []time.Duration // real time before each update; -1 resets the clock instead
+		steps  []int
+		passed []time.Duration // play time each update reports
+	}{
+		{"first update only starts the clock", []time.Duration{0, 100 * ms}, []int{0, 1}, []time.Duration{0, 100 * ms}},
+		{"steady frames", []time.Duration{0, 50 * ms, 50 * ms, 50 * ms}, []int{0, 0, 1, 0}, []time.Duration{0, 50 * ms, 50 * ms, 50 * ms}},
+		{"a stall counts as the longest frame", []time.Duration{0, 5 * time.Second}, []int{0, 2}, []time.Duration{0, maxFrameTime}},
+		{"time away is not simulated", []time.Duration{0, 90 * ms, -1, time.Minute, 20 * ms}, []int{0, 0, 0, 1}, []time.Duration{0, 90 * ms, 0, 20 * ms}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			s := NewSpeedManager()
+			s.Interval = 100 * ms
+			s.Now = func() time.Time { return now }
+			var steps []int
+			var passed []time.Duration
+			for _, gap := range tt.gaps {
+				if gap < 0 {
+					s.ResetClock()
+					continue
+				}
+				now = now.Add(gap)
+				n, dt := s.Advance()
+				steps = append(steps, n)
+				passed = append(passed, dt)
+			}
+			if !slices.Equal(steps, tt.steps) {
+				t.Errorf("steps = %v, want %v", steps, tt.steps)
+			}
+			if !slices.Equal(passed, tt.passed) {
+				t.Errorf("play time = %v, want %v", passed, tt.passed)
+			}
+		})
+	}
+}
+
+// everyTenth lists the moves due over n frames when a move takes ten frames.
+func everyTenth(n int) []int {
+	steps := make([]int, n)
+	for i := 9; i < n; i += 10 {
+		steps[i] = 1
+	}
+	return steps
+}
@@ -1,6 +1,6 @@
 package core
 
-import "fmt"
+import "time"
 
 // StateManager manages global game state such as score, level, pause, and game over flags.
 type StateManager struct {
@@ -8,6 +8,11 @@ type StateManager struct {
 	Level    int
 	Paused   bool
 	GameOver bool
+	TimeUp   bool          // the clock ran out in a timed mode
+	Elapsed  time.Duration // time spent playing, excluding pauses
+	TimeLeft time.Duration // time remaining in timed modes
+	Ticks    int           // moves simulated this run
+	Levels   LevelRule     // score-to-level rule from the selected difficulty
 }
 
 // NewStateManager initializes a new game state.
@@ -20,12 +25,10 @@ func NewStateManager() *StateManager {
 	}
 }
 
-// IncreaseScore increments the score and adjusts level as needed.
-func (s *StateManager) IncreaseScore() {
-	s.Score++
-	if s.Score%5 == 0 {
-		s.Level++
-	}
+// AddScore adds points to the score and adjusts level as needed.
+func (s *StateManager) AddScore(points int) {
+	s.Score += points
+	s.Level = s.Levels.LevelFor(s.Score)
 }
 
 // Reset restores the game state to its initial values.
@@ -34,12 +37,20 @@ func (s *StateManager) Reset() {
 	s.Level = 1
 	s.Paused = false
 	s.GameOver = false
+	s.TimeUp = false
+	s.Elapsed = 0
+	s.TimeLeft = 0
+	s.Ticks = 0
+}
+
+// LevelProgress returns how far the score is towards the next level, from 0 to 1.
+func (s *StateManager) LevelProgress() float64 {
+	return s.Levels.Progress(s.Score)
 }
 
-// TogglePause switches the pause state.
-func (s *StateManager) TogglePause() {
-	fmt.Println("Pause toggled")
-	s.Paused = !s.Paused
+// SetPaused sets the pause state explicitly.
+func (s *StateManager) SetPaused(paused bool) {
+	s.Paused = paused
 }
 
 // SetGameOver marks the game as over.
@@ -47,8 +58,13 @@ func (s *StateManager) SetGameOver() {
 	s.GameOver = true
 }
 
+// SetTimeUp ends a timed run because the clock ran out. Unlike SetGameOver the
+// snake survives, so there is no death sequence.
+func (s *StateManager) SetTimeUp() {
+	s.TimeUp = true
+}
+
 // IsRunning returns true if the game is not paused or over.
 func (s *StateManager) IsRunning() bool {
-	return !s.Paused && !s.GameOver
+	return !s.Paused && !s.GameOver && !s.TimeUp
 }
-
@@ -0,0 +1,309 @@
+package core
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"image"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"snakeGame/game/render"
+	"strconv"
+	"time"
+)
+
+// Files the statistics screen exports to.
+const (
+	statsJSONFile = "stats.json"
+	statsCSVFile  = "stats.csv"
+)
+
+// heatmapSize is the number of cells per side of a death heatmap. Deaths on boards
+// of other sizes are scaled onto it, so every map shares one heatmap per level.
+const heatmapSize = 20
+
+// LifetimeStats are totals across every recorded run. Test runs from the editor and
+// rewound runs are left out, as they are from the high scores.
+type LifetimeStats struct {
+	GamesPlayed     int                   `json:"games_played"`
+	ApplesEaten     int                   `json:"apples_eaten"`
+	LongestSnake    int                   `json:"longest_snake"`
+	LongestSurvival float64               `json:"longest_survival_seconds"`
+	Deaths          map[string]int        `json:"deaths,omitempty"`   // by cause
+	Modes           map[string]*ModeStats `json:"modes,omitempty"`    // by mode name
+	Heatmaps        map[int][]int         `json:"heatmaps,omitempty"` // deaths per heatmap cell, row by row, by level
+}
+
+// ModeStats are the totals for one game mode.
+type ModeStats struct {
+	Games      int `json:"games"`
+	TotalScore int `json:"total_score"`
+}
+
+// Average returns the mean score per game.
+func (m *ModeStats) Average() float64 {
+	if m.Games == 0 {
+		return 0
+	}
+	return float64(m.TotalScore) / float64(m.Games)
+}
+
+// runRecord is what a finished run adds to the lifetime statistics.
+type runRecord struct {
+	Mode     string
+	Score    int
+	Apples   int
+	Length   int
+	Survived time.Duration
+	Level    int
+	Cause    deathCause  // causeNone when the run ended on time
+	Pos      image.Point // cell where the snake died
+	Grid     image.Point // board size the run was played on
+}
+
+// runRecord describes the run that just finished.
+func (g *Game) runRecord() runRecord {
+	r := runRecord{
+		Mode:     g.Mode.Name,
+		Score:    g.State.Score,
+		Apples:   g.apples,
+		Length:   g.Snake.Length(),
+		Survived: g.State.Elapsed,
+		Level:    g.State.Level,
+		Grid:     image.Pt(g.gridWidth, g.gridHeight),
+	}
+	if g.State.GameOver {
+		r.Cause, r.Pos = g.deathCause, g.deathPos
+	}
+	return r
+}
+
+// RecordRun adds a finished run to the lifetime statistics.
+func (p *ProfileManager) RecordRun(r runRecord) {
+	p.Stats.record(r)
+	p.Save()
+}
+
+func (s *LifetimeStats) record(r runRecord) {
+	s.GamesPlayed++
+	s.ApplesEaten += r.Apples
+	s.LongestSnake = max(s.LongestSnake, r.Length)
+	s.LongestSurvival = max(s.LongestSurvival, r.Survived.Seconds())
+
+	if s.Modes == nil {
+		s.Modes = make(map[string]*ModeStats)
+	}
+	m := s.Modes[r.Mode]
+	if m == nil {
+		m = &ModeStats{}
+		s.Modes[r.Mode] = m
+	}
+	m.Games++
+	m.TotalScore += r.Score
+
+	if r.Cause == causeNone {
+		return
+	}
+	if s.Deaths == nil {
+		s.Deaths = make(map[string]int)
+	}
+	s.Deaths[string(r.Cause)]++
+
+	if s.Heatmaps == nil {
+		s.Heatmaps = make(map[int][]int)
+	}
+	heat := s.Heatmaps[r.Level]
+	if len(heat) != heatmapSize*heatmapSize {
+		heat = make([]int, heatmapSize*heatmapSize)
+		s.Heatmaps[r.Level] = heat
+	}
+	// Crashes into the edge happen just outside the board; they count on the edge cell.
+	x := min(max(r.Pos.X, 0), r.Grid.X-1) * heatmapSize / r.Grid.X
+	y := min(max(r.Pos.Y, 0), r.Grid.Y-1) * heatmapSize / r.Grid.Y
+	heat[y*heatmapSize+x]++
+}
+
+// levels returns the levels that have a death heatmap, lowest first.
+func (s *LifetimeStats) levels() []int {
+	levels := make([]int, 0, len(s.Heatmaps))
+	for level := range s.Heatmaps {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+	return levels
+}
+
+// modeNames returns the modes with recorded games: built-in modes in title screen
+// order, then any others such as the daily challenge by name.
+func (s *LifetimeStats) modeNames() []string {
+	var names []string
+	for _, m := range Modes {
+		if _, ok := s.Modes[m.Name]; ok {
+			names = append(names, m.Name)
+		}
+	}
+	var others []string
+	for name := range s.Modes {
+		if !slices.Contains(names, name) {
+			others = append(others, name)
+		}
+	}
+	slices.Sort(others)
+	return append(names, others...)
+}
+
+// exportJSON writes the statistics as indented JSON.
+func (s *LifetimeStats) exportJSON(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// exportCSV writes the statistics as one row per value. Heatmap rows carry the level
+// and the cell; other rows leave those columns empty.
+func (s *LifetimeStats) exportCSV(path string) error {
+	rows := [][]string{
+		{"section", "name", "level", "x", "y", "value"},
+		{"total", "games_played", "", "", "", strconv.Itoa(s.GamesPlayed)},
+		{"total", "apples_eaten", "", "", "", strconv.Itoa(s.ApplesEaten)},
+		{"total", "longest_snake", "", "", "", strconv.Itoa(s.LongestSnake)},
+		{"total", "longest_survival_seconds", "", "", "", strconv.FormatFloat(s.LongestSurvival, 'f', 1, 64)},
+	}
+	for _, cause := range deathCauses {
+		rows = append(rows, []string{"deaths", string(cause), "", "", "", strconv.Itoa(s.Deaths[string(cause)])})
+	}
+	for _, name := range s.modeNames() {
+		m := s.Modes[name]
+		rows = append(rows,
+			[]string{"mode_games", name, "", "", "", strconv.Itoa(m.Games)},
+			[]string{"mode_average_score", name, "", "", "", strconv.FormatFloat(m.Average(), 'f', 1, 64)},
+		)
+	}
+	for _, level := range s.levels() {
+		for i, count := range s.Heatmaps[level] {
+			if count == 0 {
+				continue
+			}
+			rows = append(rows, []string{"heatmap", "deaths", strconv.Itoa(level),
+				strconv.Itoa(i % heatmapSize), strconv.Itoa(i / heatmapSize), strconv.Itoa(count)})
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	if err := w.WriteAll(rows); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// statsPage is one page of the statistics screen.
+type statsPage int
+
+const (
+	statsOverview statsPage = iota
+	statsModes
+	statsHeatmap
+	statsPageCount
+)
+
+var statsPageNames = [statsPageCount]string{"Overview", "Modes", "Death Map"}
+
+// openStats shows the statistics screen from its first page.
+func (g *Game) openStats() {
+	g.statsPage = statsOverview
+	g.statsStatus = ""
+	if levels := g.Profile.Stats.levels(); len(levels) > 0 {
+		g.statsLevel = levels[0]
+	}
+	g.statsMenu.Reset()
+	g.Screens.Push(&statisticsScreen{g: g}, TransitionSlide)
+}
+
+// cycleStatsPage steps through the pages of the statistics screen.
+func (g *Game) cycleStatsPage(step int) {
+	g.statsPage = (g.statsPage + statsPage(step) + statsPageCount) % statsPageCount
+}
+
+// cycleStatsLevel steps through the levels that have a death heatmap.
+func (g *Game) cycleStatsLevel(step int) {
+	levels := g.Profile.Stats.levels()
+	if len(levels) == 0 {
+		return
+	}
+	i := slices.Index(levels, g.statsLevel)
+	g.statsLevel = levels[((i+step)%len(levels)+len(levels))%len(levels)]
+}
+
+// statsInfo returns the lines shown for the current page of the statistics screen.
+func (g *Game) statsInfo() []string {
+	s := &g.Profile.Stats
+	switch g.statsPage {
+	case statsModes:
+		var info []string
+		for _, name := range s.modeNames() {
+			m := s.Modes[name]
+			info = append(info, fmt.Sprintf("%s: %d games, avg %.1f", name, m.Games, m.Average()))
+		}
+		if len(info) == 0 {
+			info = append(info, "No games yet")
+		}
+		return info
+	case statsHeatmap:
+		heat, ok := s.Heatmaps[g.statsLevel]
+		if !ok {
+			return []string{"No deaths yet"}
+		}
+		total := 0
+		for _, count := range heat {
+			total += count
+		}
+		return []string{fmt.Sprintf("%d deaths on level %d", total, g.statsLevel)}
+	}
+	survival := time.Duration(s.LongestSurvival * float64(time.Second)).Round(time.Second)
+	return []string{
+		fmt.Sprintf("Games played: %d", s.GamesPlayed),
+		fmt.Sprintf("Apples eaten: %d", s.ApplesEaten),
+		fmt.Sprintf("Longest snake: %d", s.LongestSnake),
+		fmt.Sprintf("Longest survival: %d:%02d", int(survival.Minutes()), int(survival.Seconds())%60),
+		fmt.Sprintf("Deaths: wall %d, self %d", s.Deaths[string(causeWall)], s.Deaths[string(causeSelf)]),
+		fmt.Sprintf("obstacle %d, poison %d", s.Deaths[string(causeObstacle)], s.Deaths[string(causePoison)]),
+	}
+}
+
+// statsHeatmapView returns the heatmap to draw on the statistics screen, or nil.
+func (g *Game) statsHeatmapView() *render.Heatmap {
+	if g.statsPage != statsHeatmap {
+		return nil
+	}
+	heat, ok := g.Profile.Stats.Heatmaps[g.statsLevel]
+	if !ok {
+		return nil
+	}
+	return &render.Heatmap{Size: heatmapSize, Counts: heat}
+}
+
+// exportStats writes the statistics to path, as CSV or JSON by its extension.
+func (g *Game) exportStats(path string) {
+	var err error
+	if filepath.Ext(path) == ".csv" {
+		err = g.Profile.Stats.exportCSV(path)
+	} else {
+		err = g.Profile.Stats.exportJSON(path)
+	}
+	if err != nil {
+		log.Printf("Failed to export statistics: %v", err)
+		g.statsStatus = "Export failed"
+		return
+	}
+	log.Printf("Statistics exported to %s", path)
+	g.statsStatus = "Exported to " + path
+}
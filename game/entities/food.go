@@ -5,23 +5,123 @@ import (
 	"math/rand"
 )
 
+// FoodKind identifies the type of a food item.
+type FoodKind int
+
+const (
+	FoodApple       FoodKind = iota // +1 and grow
+	FoodGoldenApple                 // +5 and grow, expires
+	FoodBerry                       // shrinks the snake
+	FoodPepper                      // temporary speed up
+	FoodMushroom                    // temporary slow down
+	FoodPoison                      // ends the run
+	FoodClock                       // adds time in timed modes
+)
+
+// FoodSpec describes how a kind of food behaves and how often it appears.
+type FoodSpec struct {
+	Sprite   string // theme sprite key
+	Points   int    // score awarded when eaten
+	Weight   int    // relative spawn weight among special foods
+	Lifetime int    // moves before it disappears; 0 = never
+}
+
+// FoodSpecs lists every food kind. Apples are always on the board; the other kinds
+// are rolled by weight when an apple is eaten, with the apple weight meaning "none".
+var FoodSpecs = map[FoodKind]FoodSpec{
+	FoodApple:       {Sprite: "apple", Points: 1, Weight: 60},
+	FoodGoldenApple: {Sprite: "golden_apple", Points: 5, Weight: 8, Lifetime: 40},
+	FoodBerry:       {Sprite: "berry", Points: 1, Weight: 10, Lifetime: 60},
+	FoodPepper:      {Sprite: "pepper", Points: 1, Weight: 8, Lifetime: 60},
+	FoodMushroom:    {Sprite: "mushroom", Points: 1, Weight: 8, Lifetime: 60},
+	FoodPoison:      {Sprite: "poison", Points: 0, Weight: 6, Lifetime: 50},
+	FoodClock:       {Sprite: "clock", Points: 0, Weight: 0, Lifetime: 40}, // spawned by timed modes only
+}
+
+// foodKinds fixes the iteration order for weighted rolls.
+var foodKinds = []FoodKind{FoodApple, FoodGoldenApple, FoodBerry, FoodPepper, FoodMushroom, FoodPoison, FoodClock}
+
 // Food represents a food item that the snake can eat.
 type Food struct {
-	Pos image.Point // grid position of the food
-}
-
-// NewFood generates a Food item at a random position not occupied by the snake.
-func NewFood(snake *SnakeController, gridWidth, gridHeight int) *Food {
-	for {
-		// Pick a random position within the grid.
-		x := rand.Intn(gridWidth)
-		y := rand.Intn(gridHeight)
-		candidate := image.Point{X: x, Y: y}
-		// Ensure the random position is not on the snake.
-		if !snake.Occupies(candidate) {
-			return &Food{Pos: candidate}
+	Pos       image.Point // grid position of the food
+	Kind      FoodKind
+	TicksLeft int // moves until it expires; 0 = never expires
+	Age       int // moves since it spawned
+}
+
+// NewFoodOfKind generates a food of the given kind at a random cell for which
+// blocked returns false. It returns false if every cell is blocked.
+func NewFoodOfKind(rng *rand.Rand, kind FoodKind, gridWidth, gridHeight int, blocked func(image.Point) bool) (*Food, bool) {
+	pos, ok := randomFreeCell(rng, gridWidth, gridHeight, blocked)
+	if !ok {
+		return nil, false
+	}
+	return &Food{Pos: pos, Kind: kind, TicksLeft: FoodSpecs[kind].Lifetime}, true
+}
+
+// randomFreeCell picks a random cell for which blocked returns false. Random picks
+// are tried first; once they keep hitting blocked cells, it chooses among the free
+// cells that are left, and returns false if there are none.
+func randomFreeCell(rng *rand.Rand, gridWidth, gridHeight int, blocked func(image.Point) bool) (image.Point, bool) {
+	for attempt := 0; attempt < gridWidth*gridHeight; attempt++ {
+		candidate := image.Point{X: rng.Intn(gridWidth), Y: rng.Intn(gridHeight)}
+		if !blocked(candidate) {
+			return candidate, true
+		}
+	}
+	var free []image.Point
+	for y := 0; y < gridHeight; y++ {
+		for x := 0; x < gridWidth; x++ {
+			if p := image.Pt(x, y); !blocked(p) {
+				free = append(free, p)
+			}
 		}
-		// If it collides with the snake, loop and try another position.
 	}
+	if len(free) == 0 {
+		return image.Point{}, false
+	}
+	return free[rng.Intn(len(free))], true
+}
+
+// RandomFoodKind picks a food kind by spawn weight. weights overrides the weights
+// in FoodSpecs for the kinds it lists.
+func RandomFoodKind(rng *rand.Rand, weights map[FoodKind]int) FoodKind {
+	weight := func(kind FoodKind) int {
+		if w, ok := weights[kind]; ok {
+			return w
+		}
+		return FoodSpecs[kind].Weight
+	}
+	total := 0
+	for _, kind := range foodKinds {
+		total += weight(kind)
+	}
+	roll := rng.Intn(total)
+	for _, kind := range foodKinds {
+		roll -= weight(kind)
+		if roll < 0 {
+			return kind
+		}
+	}
+	return FoodApple
+}
+
+// Spec returns the behavior description for this food's kind.
+func (f *Food) Spec() FoodSpec {
+	return FoodSpecs[f.Kind]
 }
 
+// Tick counts down the food's lifetime and returns true once it has expired.
+func (f *Food) Tick() bool {
+	f.Age++
+	if f.TicksLeft == 0 {
+		return false
+	}
+	f.TicksLeft--
+	return f.TicksLeft == 0
+}
+
+// Expiring reports whether the food is about to disappear, for blinking.
+func (f *Food) Expiring() bool {
+	return f.TicksLeft > 0 && f.TicksLeft <= 10
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"image"
+	"math/rand"
+)
+
+// Hazard is an obstacle that appears on the board during a run. It blinks as a
+// warning for a number of moves before it becomes solid.
+type Hazard struct {
+	Pos     image.Point
+	Warning int // moves left before it becomes solid
+}
+
+// NewHazard places a hazard at a random cell for which blocked returns false. It
+// gives up and returns nil after a bounded number of attempts, since hazards may
+// legitimately fill the board.
+func NewHazard(rng *rand.Rand, gridWidth, gridHeight, warning int, blocked func(image.Point) bool) *Hazard {
+	for attempt := 0; attempt < gridWidth*gridHeight; attempt++ {
+		candidate := image.Point{X: rng.Intn(gridWidth), Y: rng.Intn(gridHeight)}
+		if !blocked(candidate) {
+			return &Hazard{Pos: candidate, Warning: warning}
+		}
+	}
+	return nil
+}
+
+// Solid reports whether the hazard has finished warning and now blocks the snake.
+func (h *Hazard) Solid() bool {
+	return h.Warning <= 0
+}
+
+// Tick counts down the warning by one move.
+func (h *Hazard) Tick() {
+	if h.Warning > 0 {
+		h.Warning--
+	}
+}
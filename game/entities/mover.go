@@ -0,0 +1,140 @@
+package entities
+
+import "image"
+
+// MoverKind identifies how a moving obstacle travels.
+type MoverKind int
+
+const (
+	MoverPatrol  MoverKind = iota // walks along a fixed path of waypoints
+	MoverBouncer                  // travels in a straight line and bounces off obstacles
+)
+
+// Mover is an enemy that moves on its own and kills the snake on contact.
+type Mover struct {
+	Kind  MoverKind
+	Pos   image.Point
+	Dir   image.Point   // travel direction for bouncers
+	Path  []image.Point // waypoints for patrols
+	Loop  bool          // patrols return to the first waypoint instead of reversing
+	Every int           // snake moves per mover step; 1 = same speed as the snake
+	next  int           // index of the waypoint a patrol is heading to
+	back  bool          // a non-looping patrol is walking its path in reverse
+	wait  int           // snake moves since the last step
+}
+
+// MoverProgress is where a mover is along its route, kept when a game is saved.
+type MoverProgress struct {
+	Next int  // index of the waypoint a patrol is heading to
+	Back bool // a non-looping patrol is walking its path in reverse
+	Wait int  // snake moves since the last step
+}
+
+// Progress returns where the mover is along its route.
+func (m *Mover) Progress() MoverProgress {
+	return MoverProgress{Next: m.next, Back: m.back, Wait: m.wait}
+}
+
+// SetProgress puts the mover back at a saved point along its route.
+func (m *Mover) SetProgress(p MoverProgress) {
+	m.next, m.back, m.wait = p.Next, p.Back, p.Wait
+}
+
+// NewPatrol creates a patrol starting on the first waypoint of path.
+func NewPatrol(path []image.Point, loop bool, every int) *Mover {
+	m := &Mover{Kind: MoverPatrol, Pos: path[0], Path: path, Loop: loop, Every: every}
+	if len(path) > 1 {
+		m.next = 1
+	}
+	return m
+}
+
+// NewBouncer creates a bouncer at pos travelling in dir.
+func NewBouncer(pos, dir image.Point, every int) *Mover {
+	return &Mover{Kind: MoverBouncer, Pos: pos, Dir: dir, Every: every}
+}
+
+// Tick advances the mover by one snake move, stepping once every Every moves.
+// blocked reports cells the mover may not enter; it waits there instead.
+func (m *Mover) Tick(blocked func(image.Point) bool) {
+	m.wait++
+	if m.wait < m.Every {
+		return
+	}
+	m.wait = 0
+	switch m.Kind {
+	case MoverPatrol:
+		m.stepPatrol(blocked)
+	case MoverBouncer:
+		m.stepBouncer(blocked)
+	}
+}
+
+// stepPatrol moves one cell towards the next waypoint, horizontally first.
+func (m *Mover) stepPatrol(blocked func(image.Point) bool) {
+	if len(m.Path) < 2 {
+		return
+	}
+	target := m.Path[m.next]
+	d := target.Sub(m.Pos)
+	step := image.Pt(Sign(d.X), 0)
+	if d.X == 0 {
+		step = image.Pt(0, Sign(d.Y))
+	}
+	if to := m.Pos.Add(step); !blocked(to) {
+		m.Pos = to
+	}
+	if m.Pos == target {
+		m.advanceWaypoint()
+	}
+}
+
+// advanceWaypoint picks the next waypoint, looping or reversing at the path's end.
+func (m *Mover) advanceWaypoint() {
+	last := len(m.Path) - 1
+	switch {
+	case m.Loop:
+		m.next = (m.next + 1) % len(m.Path)
+	case m.back && m.next == 0:
+		m.back = false
+		m.next = 1
+	case !m.back && m.next == last:
+		m.back = true
+		m.next = last - 1
+	case m.back:
+		m.next--
+	default:
+		m.next++
+	}
+}
+
+// stepBouncer moves one cell along Dir, reflecting off whatever is in the way.
+func (m *Mover) stepBouncer(blocked func(image.Point) bool) {
+	tries := []image.Point{
+		m.Dir,
+		image.Pt(-m.Dir.X, m.Dir.Y),
+		image.Pt(m.Dir.X, -m.Dir.Y),
+		image.Pt(-m.Dir.X, -m.Dir.Y),
+	}
+	for _, dir := range tries {
+		if dir == (image.Point{}) {
+			continue
+		}
+		if to := m.Pos.Add(dir); !blocked(to) {
+			m.Dir = dir
+			m.Pos = to
+			return
+		}
+	}
+}
+
+// Sign returns -1, 0 or 1 for negative, zero and positive v.
+func Sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,20 @@
+package entities
+
+import "image"
+
+// Portal links two cells: a snake entering either end comes out of the other,
+// keeping its direction.
+type Portal struct {
+	A, B image.Point
+}
+
+// Exit returns the cell linked to pos, or false if pos is not an end of this portal.
+func (p Portal) Exit(pos image.Point) (image.Point, bool) {
+	switch pos {
+	case p.A:
+		return p.B, true
+	case p.B:
+		return p.A, true
+	}
+	return image.Point{}, false
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"image"
+	"math/rand"
+)
+
+// PowerUpKind identifies the type of a power-up.
+type PowerUpKind int
+
+const (
+	PowerGhost      PowerUpKind = iota // pass through the snake's own body and enemies
+	PowerShield                        // survive one wall hit by wrapping around, or smash an enemy
+	PowerMagnet                        // pull nearby food towards the head
+	PowerMultiplier                    // multiply points scored
+	PowerSlowTime                      // slow the snake down
+)
+
+// PowerUpSpec describes a power-up and how repeated pickups stack.
+type PowerUpSpec struct {
+	Name      string // shown in the HUD
+	Sprite    string // theme sprite key
+	Weight    int    // relative spawn weight
+	Lifetime  int    // moves it stays on the board before disappearing
+	Duration  int    // moves the effect lasts once collected
+	MaxStacks int    // pickups that stack; further pickups only refresh the duration
+}
+
+// PowerUpSpecs lists every power-up kind.
+var PowerUpSpecs = map[PowerUpKind]PowerUpSpec{
+	PowerGhost:      {Name: "Ghost", Sprite: "ghost", Weight: 20, Lifetime: 50, Duration: 40, MaxStacks: 1},
+	PowerShield:     {Name: "Shield", Sprite: "shield", Weight: 20, Lifetime: 50, Duration: 120, MaxStacks: 3},
+	PowerMagnet:     {Name: "Magnet", Sprite: "magnet", Weight: 20, Lifetime: 50, Duration: 60, MaxStacks: 1},
+	PowerMultiplier: {Name: "Score", Sprite: "multiplier", Weight: 20, Lifetime: 50, Duration: 60, MaxStacks: 3},
+	PowerSlowTime:   {Name: "Slow", Sprite: "slow_time", Weight: 20, Lifetime: 50, Duration: 40, MaxStacks: 1},
+}
+
+// PowerUpKinds fixes the iteration order of power-up kinds.
+var PowerUpKinds = []PowerUpKind{PowerGhost, PowerShield, PowerMagnet, PowerMultiplier, PowerSlowTime}
+
+// PowerUp is a power-up waiting on the board to be collected.
+type PowerUp struct {
+	Pos       image.Point
+	Kind      PowerUpKind
+	TicksLeft int // moves until it disappears
+}
+
+// NewPowerUp places a random power-up at a random cell for which blocked returns
+// false. It returns false if every cell is blocked.
+func NewPowerUp(rng *rand.Rand, gridWidth, gridHeight int, blocked func(image.Point) bool) (*PowerUp, bool) {
+	kind := randomPowerUpKind(rng)
+	pos, ok := randomFreeCell(rng, gridWidth, gridHeight, blocked)
+	if !ok {
+		return nil, false
+	}
+	return &PowerUp{Pos: pos, Kind: kind, TicksLeft: PowerUpSpecs[kind].Lifetime}, true
+}
+
+// randomPowerUpKind picks a power-up kind by spawn weight.
+func randomPowerUpKind(rng *rand.Rand) PowerUpKind {
+	total := 0
+	for _, kind := range PowerUpKinds {
+		total += PowerUpSpecs[kind].Weight
+	}
+	roll := rng.Intn(total)
+	for _, kind := range PowerUpKinds {
+		roll -= PowerUpSpecs[kind].Weight
+		if roll < 0 {
+			return kind
+		}
+	}
+	return PowerGhost
+}
+
+// Spec returns the description of this power-up's kind.
+func (p *PowerUp) Spec() PowerUpSpec {
+	return PowerUpSpecs[p.Kind]
+}
+
+// Tick counts down the time left on the board and returns true once it has expired.
+func (p *PowerUp) Tick() bool {
+	p.TicksLeft--
+	return p.TicksLeft <= 0
+}
+
+// Expiring reports whether the power-up is about to disappear, for blinking.
+func (p *PowerUp) Expiring() bool {
+	return p.TicksLeft <= 10
+}
@@ -28,6 +28,7 @@ type SnakeSegment struct {
 	Pos      image.Point
 	Tile     TileType
 	Rotation float64
+	Dir      image.Point // direction travelled to enter this cell from Next; works across jumps
 	Prev     *SnakeSegment
 	Next     *SnakeSegment
 }
@@ -45,23 +46,33 @@ type SnakeController struct {
 
 // NewSnakeController sets up a snake with head, 2 body segments, and a tail
 func NewSnakeController(start image.Point, gridWidth, gridHeight int) *SnakeController {
+	return NewSnakeControllerFacing(start, Right, gridWidth, gridHeight)
+}
+
+// NewSnakeControllerFacing sets up the same snake heading in dir, with its body
+// laid out behind the head
+func NewSnakeControllerFacing(start, dir image.Point, gridWidth, gridHeight int) *SnakeController {
+	angle := directionToAngle(dir)
 	tail := &SnakeSegment{
-		Pos:      image.Pt(start.X-3, start.Y),
+		Pos:      start.Sub(dir.Mul(3)),
 		Tile:     TileTail,
-		Rotation: 270.0,
+		Rotation: directionToAngle(dir.Mul(-1)),
+		Dir:      dir,
 	}
 	body2 := &SnakeSegment{
-		Pos:      image.Pt(start.X-2, start.Y),
+		Pos:      start.Sub(dir.Mul(2)),
 		Tile:     TileBody,
-		Rotation: 90.0,
+		Rotation: angle,
+		Dir:      dir,
 		Next:     tail,
 	}
 	tail.Prev = body2
 
 	body1 := &SnakeSegment{
-		Pos:      image.Pt(start.X-1, start.Y),
+		Pos:      start.Sub(dir),
 		Tile:     TileBody,
-		Rotation: 90.0,
+		Rotation: angle,
+		Dir:      dir,
 		Next:     body2,
 	}
 	body2.Prev = body1
@@ -69,7 +80,8 @@ func NewSnakeController(start image.Point, gridWidth, gridHeight int) *SnakeCont
 	head := &SnakeSegment{
 		Pos:      start,
 		Tile:     TileHead,
-		Rotation: 90.0,
+		Rotation: angle,
+		Dir:      dir,
 		Next:     body1,
 	}
 	body1.Prev = head
@@ -77,8 +89,8 @@ func NewSnakeController(start image.Point, gridWidth, gridHeight int) *SnakeCont
 	return &SnakeController{
 		Head:       head,
 		Tail:       tail,
-		Dir:        Right,
-		PendingDir: Right,
+		Dir:        dir,
+		PendingDir: dir,
 		GridWidth:  gridWidth,
 		GridHeight: gridHeight,
 	}
@@ -87,12 +99,18 @@ func NewSnakeController(start image.Point, gridWidth, gridHeight int) *SnakeCont
 // MoveForward shifts the snake forward
 func (sc *SnakeController) MoveForward() {
 	sc.Dir = sc.PendingDir
-	newHeadPos := sc.Head.Pos.Add(sc.Dir)
+	sc.MoveTo(sc.Head.Pos.Add(sc.Dir))
+}
 
+// MoveTo advances the snake one step with the head landing on newHeadPos, for
+// moves that do not simply follow the direction (e.g. wrapping around the board or
+// through a portal). The head keeps its direction.
+func (sc *SnakeController) MoveTo(newHeadPos image.Point) {
 	newHead := &SnakeSegment{
 		Pos:      newHeadPos,
 		Tile:     TileHead,
 		Rotation: directionToAngle(sc.Dir),
+		Dir:      sc.Dir,
 		Next:     sc.Head,
 	}
 	sc.Head.Prev = newHead
@@ -108,8 +126,7 @@ func (sc *SnakeController) MoveForward() {
 
 			// should face AWAY from previous segment
 			if sc.Tail.Prev != nil {
-				dir := sc.Tail.Prev.Pos.Sub(sc.Tail.Pos)
-				sc.Tail.Rotation = directionToAngle(dir)
+				sc.Tail.Rotation = directionToAngle(sc.Tail.Prev.Dir)
 			}
 
 		}
@@ -130,12 +147,10 @@ func (sc *SnakeController) assignBends() {
 	sc.Head.Rotation = directionToAngle(sc.Dir)
 
 	for seg := sc.Head.Next; seg != nil && seg.Next != nil; seg = seg.Next {
-		prev := seg.Prev.Pos
-		curr := seg.Pos
-		next := seg.Next.Pos
-
-		dir1 := prev.Sub(curr)
-		dir2 := next.Sub(curr)
+		// Neighbors are found from the directions travelled rather than by position,
+		// since a segment that came through a portal is not next to the one before it.
+		dir1 := seg.Prev.Dir    // towards the previous segment
+		dir2 := seg.Dir.Mul(-1) // towards the next segment
 
 		if dir1.X == dir2.X || dir1.Y == dir2.Y {
 			// Straight
@@ -153,6 +168,16 @@ func (sc *SnakeController) assignBends() {
 	}
 }
 
+// Shrink removes up to n segments from the tail, never going below minLength
+func (sc *SnakeController) Shrink(n, minLength int) {
+	for i := 0; i < n && sc.Length() > minLength; i++ {
+		sc.Tail = sc.Tail.Prev
+		sc.Tail.Next = nil
+		sc.Tail.Tile = TileTail
+		sc.Tail.Rotation = directionToAngle(sc.Tail.Prev.Dir)
+	}
+}
+
 // Grow triggers growth on next move
 func (sc *SnakeController) Grow() {
 	sc.Growing = true
@@ -168,6 +193,14 @@ func (sc *SnakeController) NextHeadPosition() image.Point {
 	return sc.Head.Pos.Add(sc.Dir)
 }
 
+// UpcomingDir returns the direction the snake will take on its next move
+func (sc *SnakeController) UpcomingDir() image.Point {
+	if sc.CanMoveTo(sc.PendingDir, sc.GridWidth, sc.GridHeight) {
+		return sc.PendingDir
+	}
+	return sc.Dir
+}
+
 // ApplyPendingDirection sets new direction if valid
 func (sc *SnakeController) ApplyPendingDirection(gridWidth, gridHeight int) {
 	if sc.CanMoveTo(sc.PendingDir, gridWidth, gridHeight) {
@@ -181,6 +214,15 @@ func (sc *SnakeController) CanMoveTo(dir image.Point, gridWidth, gridHeight int)
 	return next.X >= 0 && next.X < gridWidth && next.Y >= 0 && next.Y < gridHeight
 }
 
+// Length returns the number of segments, head and tail included
+func (sc *SnakeController) Length() int {
+	n := 0
+	for seg := sc.Head; seg != nil; seg = seg.Next {
+		n++
+	}
+	return n
+}
+
 // Occupies returns true if a point is occupied
 func (sc *SnakeController) Occupies(pt image.Point) bool {
 	seg := sc.Head
@@ -0,0 +1,170 @@
+package render
+
+import (
+	"image"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// MenuItemKind selects how a menu item reacts to input and how it is drawn.
+type MenuItemKind int
+
+const (
+	MenuAction MenuItemKind = iota // runs OnSelect on confirm
+	MenuToggle                     // flips with confirm or left/right
+	MenuChoice                     // cycles through values with left/right
+	MenuSlider                     // adjusts a 0..1 value with left/right
+	MenuLabel                      // static text, skipped by navigation
+	MenuText                       // shows editable text; runs OnSelect on confirm to start typing
+)
+
+// MenuItem is one row of a Menu. Value, Step and Level are read lazily so the menu
+// always reflects the current state without being rebuilt.
+type MenuItem struct {
+	Label    string
+	Kind     MenuItemKind
+	OnSelect func()         // MenuAction and MenuText: called on confirm
+	OnChange func(step int) // toggles, choices and sliders: called with -1 or +1
+	Value    func() string  // toggles, choices and text: shown after the label; labels: replaces Label
+	Level    func() float64 // sliders: fill amount from 0 to 1
+	Hidden   func() bool    // optional: hide the row entirely when true
+}
+
+// Menu is a vertical list of items with keyboard, mouse and gamepad navigation.
+type Menu struct {
+	Title    string
+	Items    []*MenuItem
+	Selected int
+	OnBack   func() // called on Escape / gamepad B; nil disables back navigation
+
+	rects      map[*MenuItem]image.Rectangle // item bounds from the last draw, for mouse input
+	lastCursor image.Point
+}
+
+// NewMenu creates a menu with the first selectable item focused.
+func NewMenu(title string, items ...*MenuItem) *Menu {
+	m := &Menu{Title: title, Items: items}
+	m.Reset()
+	return m
+}
+
+// Reset moves focus back to the first selectable item.
+func (m *Menu) Reset() {
+	m.Selected = -1
+	m.move(1)
+}
+
+// visible reports whether the item is currently shown.
+func (item *MenuItem) visible() bool {
+	return item.Hidden == nil || !item.Hidden()
+}
+
+// selectable reports whether the item can take focus.
+func (item *MenuItem) selectable() bool {
+	return item.Kind != MenuLabel && item.visible()
+}
+
+// move shifts focus by step, wrapping around and skipping unselectable items.
+func (m *Menu) move(step int) {
+	n := len(m.Items)
+	for i := 1; i <= n; i++ {
+		idx := ((m.Selected+step*i)%n + n) % n
+		if m.Items[idx].selectable() {
+			m.Selected = idx
+			return
+		}
+	}
+}
+
+// menuInput is the set of navigation actions pressed this frame from any device.
+type menuInput struct {
+	up, down, left, right, confirm, back bool
+}
+
+func readMenuInput() menuInput {
+	in := menuInput{
+		up:      inpututil.IsKeyJustPressed(ebiten.KeyArrowUp) || inpututil.IsKeyJustPressed(ebiten.KeyW),
+		down:    inpututil.IsKeyJustPressed(ebiten.KeyArrowDown) || inpututil.IsKeyJustPressed(ebiten.KeyS),
+		left:    inpututil.IsKeyJustPressed(ebiten.KeyArrowLeft) || inpututil.IsKeyJustPressed(ebiten.KeyA),
+		right:   inpututil.IsKeyJustPressed(ebiten.KeyArrowRight) || inpututil.IsKeyJustPressed(ebiten.KeyD),
+		confirm: inpututil.IsKeyJustPressed(ebiten.KeyEnter) || inpututil.IsKeyJustPressed(ebiten.KeySpace),
+		back:    inpututil.IsKeyJustPressed(ebiten.KeyEscape) || inpututil.IsKeyJustPressed(ebiten.KeyBackspace),
+	}
+	for _, id := range ebiten.AppendGamepadIDs(nil) {
+		if !ebiten.IsStandardGamepadLayoutAvailable(id) {
+			continue
+		}
+		pressed := func(b ebiten.StandardGamepadButton) bool {
+			return inpututil.IsStandardGamepadButtonJustPressed(id, b)
+		}
+		in.up = in.up || pressed(ebiten.StandardGamepadButtonLeftTop)
+		in.down = in.down || pressed(ebiten.StandardGamepadButtonLeftBottom)
+		in.left = in.left || pressed(ebiten.StandardGamepadButtonLeftLeft)
+		in.right = in.right || pressed(ebiten.StandardGamepadButtonLeftRight)
+		in.confirm = in.confirm || pressed(ebiten.StandardGamepadButtonRightBottom)
+		in.back = in.back || pressed(ebiten.StandardGamepadButtonRightRight)
+	}
+	return in
+}
+
+// Update handles navigation for one frame and fires item callbacks.
+func (m *Menu) Update() {
+	if m.Selected < 0 || m.Selected >= len(m.Items) || !m.Items[m.Selected].selectable() {
+		m.Reset()
+	}
+	in := readMenuInput()
+
+	// Mouse: hovering focuses an item, clicking activates it.
+	clicked := false
+	cursor := image.Pt(ebiten.CursorPosition())
+	moved := cursor != m.lastCursor
+	m.lastCursor = cursor
+	for i, item := range m.Items {
+		r, ok := m.rects[item]
+		if !ok || !item.selectable() || !cursor.In(r) {
+			continue
+		}
+		if moved {
+			m.Selected = i
+		}
+		if inpututil.IsMouseButtonJustPressed(ebiten.MouseButtonLeft) {
+			m.Selected = i
+			clicked = true
+		}
+	}
+
+	switch {
+	case in.up:
+		m.move(-1)
+	case in.down:
+		m.move(1)
+	case in.back && m.OnBack != nil:
+		m.OnBack()
+		return
+	}
+	if m.Selected < 0 {
+		return
+	}
+
+	item := m.Items[m.Selected]
+	step := 0
+	if in.left {
+		step = -1
+	} else if in.right {
+		step = 1
+	}
+	switch item.Kind {
+	case MenuAction, MenuText:
+		if (in.confirm || clicked) && item.OnSelect != nil {
+			item.OnSelect()
+		}
+	case MenuToggle, MenuChoice, MenuSlider:
+		if (in.confirm || clicked) && item.Kind != MenuSlider {
+			step = 1
+		}
+		if step != 0 && item.OnChange != nil {
+			item.OnChange(step)
+		}
+	}
+}
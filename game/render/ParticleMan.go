@@ -0,0 +1,175 @@
+package render
+
+import (
+	"image/color"
+	"math"
+	"math/rand"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// particle is a single short-lived square that drifts and fades out.
+type particle struct {
+	x, y, vx, vy  float64
+	life, maxLife int
+	size          float32
+	color         color.RGBA
+}
+
+// floatingText is a label such as "+1" that rises and disappears.
+type floatingText struct {
+	text string
+	x, y float64
+	life int
+}
+
+// ParticleManager runs lightweight visual effects: particle bursts, floating text,
+// screen shake and a full-screen flash. All emit calls are ignored when disabled.
+type ParticleManager struct {
+	Enabled   bool
+	ui        *UIManager // draws floating text
+	particles []*particle
+	texts     []*floatingText
+	shake     int     // frames of shake remaining
+	shakeMag  float64 // shake strength in pixels
+	flash     int     // frames of flash remaining
+	flashMax  int
+}
+
+// NewParticleManager creates an enabled particle manager that draws text through ui.
+func NewParticleManager(ui *UIManager) *ParticleManager {
+	return &ParticleManager{Enabled: true, ui: ui}
+}
+
+// Burst throws count particles outwards from the pixel position (x, y).
+func (pm *ParticleManager) Burst(x, y float64, count int, c color.RGBA) {
+	if !pm.Enabled {
+		return
+	}
+	for i := 0; i < count; i++ {
+		angle := rand.Float64() * 2 * math.Pi
+		speed := 0.5 + rand.Float64()*1.5
+		life := 20 + rand.Intn(15)
+		pm.particles = append(pm.particles, &particle{
+			x: x, y: y,
+			vx: math.Cos(angle) * speed, vy: math.Sin(angle) * speed,
+			life: life, maxLife: life,
+			size:  2,
+			color: c,
+		})
+	}
+}
+
+// Sparkle scatters slow twinkling particles across the given area, used on level up.
+func (pm *ParticleManager) Sparkle(width, height int) {
+	if !pm.Enabled {
+		return
+	}
+	for i := 0; i < 40; i++ {
+		life := 30 + rand.Intn(30)
+		pm.particles = append(pm.particles, &particle{
+			x: rand.Float64() * float64(width), y: rand.Float64() * float64(height),
+			vy:   -0.3,
+			life: life, maxLife: life,
+			size:  1,
+			color: color.RGBA{R: 0xff, G: 0xf0, B: 0x80, A: 0xff},
+		})
+	}
+}
+
+// FloatText shows a rising label such as "+1" at the pixel position (x, y).
+func (pm *ParticleManager) FloatText(text string, x, y float64) {
+	if !pm.Enabled {
+		return
+	}
+	pm.texts = append(pm.texts, &floatingText{text: text, x: x, y: y, life: 40})
+}
+
+// Shake jolts the screen for the given number of frames.
+func (pm *ParticleManager) Shake(frames int, magnitude float64) {
+	if !pm.Enabled {
+		return
+	}
+	pm.shake = frames
+	pm.shakeMag = magnitude
+}
+
+// Flash briefly washes the screen white for the given number of frames.
+func (pm *ParticleManager) Flash(frames int) {
+	if !pm.Enabled {
+		return
+	}
+	pm.flash = frames
+	pm.flashMax = frames
+}
+
+// Update advances all effects by one frame.
+func (pm *ParticleManager) Update() {
+	alive := pm.particles[:0]
+	for _, p := range pm.particles {
+		p.x += p.vx
+		p.y += p.vy
+		p.vx *= 0.95
+		p.vy *= 0.95
+		p.life--
+		if p.life > 0 {
+			alive = append(alive, p)
+		}
+	}
+	pm.particles = alive
+
+	texts := pm.texts[:0]
+	for _, t := range pm.texts {
+		t.y -= 0.5
+		t.life--
+		if t.life > 0 {
+			texts = append(texts, t)
+		}
+	}
+	pm.texts = texts
+
+	if pm.shake > 0 {
+		pm.shake--
+	}
+	if pm.flash > 0 {
+		pm.flash--
+	}
+}
+
+// ShakeOffset returns the pixel offset to draw the board at while the screen shakes.
+func (pm *ParticleManager) ShakeOffset() (float64, float64) {
+	if pm.shake <= 0 {
+		return 0, 0
+	}
+	return (rand.Float64()*2 - 1) * pm.shakeMag, (rand.Float64()*2 - 1) * pm.shakeMag
+}
+
+// Draw renders particles, floating text and the flash overlay.
+func (pm *ParticleManager) Draw(screen *ebiten.Image) {
+	for _, p := range pm.particles {
+		c := p.color
+		c.A = uint8(float64(c.A) * float64(p.life) / float64(p.maxLife))
+		vector.DrawFilledRect(screen, float32(p.x), float32(p.y), p.size, p.size, c, false)
+	}
+	for _, t := range pm.texts {
+		alpha := uint8(0xff * t.life / 40)
+		style := TextStyle{Size: FontSizeBody, Bold: true, Align: text.AlignCenter, Outline: true,
+			Color: color.RGBA{R: alpha, G: alpha, B: alpha, A: alpha}}
+		pm.ui.DrawText(screen, t.text, t.x, t.y, style)
+	}
+	if pm.flash > 0 {
+		alpha := uint8(0xc0 * pm.flash / pm.flashMax)
+		w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+		vector.DrawFilledRect(screen, 0, 0, float32(w), float32(h), color.RGBA{R: alpha, G: alpha, B: alpha, A: alpha}, false)
+	}
+}
+
+// Clear removes every active effect, e.g. when a new game starts.
+func (pm *ParticleManager) Clear() {
+	pm.particles = nil
+	pm.texts = nil
+	pm.shake = 0
+	pm.flash = 0
+}
@@ -2,48 +2,198 @@ package render
 
 import (
 	"github.com/hajimehoshi/ebiten/v2"
+	"image/color"
 	"math"
 	"snakeGame/game/entities"
 )
 
 type Renderer struct {
 	SpriteManager *SpriteManager
+	Interpolate   bool          // slide segments between cells instead of snapping every tick
+	ghostLayer    *ebiten.Image // off-screen image the ghost is drawn to before tinting
 }
 
 func NewRenderer(sm *SpriteManager) *Renderer {
 	return &Renderer{SpriteManager: sm}
 }
 
-// DrawSnake renders the entire snake segment-by-segment
-func (r *Renderer) DrawSnake(screen *ebiten.Image, sc *entities.SnakeController) {
+// DrawSnake renders the entire snake segment-by-segment. progress is the fraction of
+// the way to the next tick and is only used when Interpolate is enabled.
+func (r *Renderer) DrawSnake(screen *ebiten.Image, sc *entities.SnakeController, progress float64) {
+	if r.Interpolate && progress > 0 {
+		r.drawSnakeSmooth(screen, sc, progress)
+		return
+	}
+
+	index := 0
 	for seg := sc.Head; seg != nil; seg = seg.Next {
-		var spriteType SnakePart
-		var angle float64
-
-		switch seg.Tile {
-		case entities.TileHead:
-			spriteType = Head
-			angle = seg.Rotation
-		case entities.TileTail:
-			spriteType = Tail
-			angle = seg.Rotation + 180
-		case entities.TileBend:
-			spriteType = Bend
-			angle = seg.Rotation
-		case entities.TileBody:
-			// Determine if body is horizontal or vertical for sprite (optional if using single sprite + rotation)
-			if seg.Rotation == 90.0 || seg.Rotation == 270.0 {
-				spriteType = BodyHorizontal
-				angle = seg.Rotation
-			} else {
-				spriteType = BodyVertical
-				angle = seg.Rotation
-			}
-		default:
-			spriteType = BodyVertical // fallback
-			angle = seg.Rotation
+		part, angle := segmentSprite(seg)
+		r.SpriteManager.DrawSegment(screen, part, seg.Pos, angle*math.Pi/180, index)
+		index++
+	}
+}
+
+// segmentSprite picks the sprite for a segment and the angle in degrees to draw it at.
+func segmentSprite(seg *entities.SnakeSegment) (SnakePart, float64) {
+	switch seg.Tile {
+	case entities.TileHead:
+		return Head, seg.Rotation
+	case entities.TileTail:
+		return Tail, seg.Rotation + 180
+	case entities.TileBend:
+		return Bend, seg.Rotation
+	case entities.TileBody:
+		// Determine if body is horizontal or vertical for sprite (optional if using single sprite + rotation)
+		if seg.Rotation == 90.0 || seg.Rotation == 270.0 {
+			return BodyHorizontal, seg.Rotation
+		}
+		return BodyVertical, seg.Rotation
+	default:
+		return BodyVertical, seg.Rotation // fallback
+	}
+}
+
+// DrawGhost draws a snake as a translucent silhouette in the tint color. The snake is
+// drawn off-screen first and then blended in one pass, so overlapping sprites do not
+// show through each other.
+func (r *Renderer) DrawGhost(screen *ebiten.Image, sc *entities.SnakeController, progress float64, tint color.Color, alpha float32) {
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	if r.ghostLayer == nil || r.ghostLayer.Bounds().Dx() != w || r.ghostLayer.Bounds().Dy() != h {
+		r.ghostLayer = ebiten.NewImage(w, h)
+	}
+	r.ghostLayer.Clear()
+	r.DrawSnake(r.ghostLayer, sc, progress)
+
+	op := &ebiten.DrawImageOptions{}
+	op.ColorScale.ScaleWithColor(tint)
+	op.ColorScale.ScaleAlpha(alpha)
+	screen.DrawImage(r.ghostLayer, op)
+}
+
+// DrawSnakeDying draws only the first visible segments counted from the head, so the
+// snake appears to collapse from the tail. The head is skipped when headVisible is
+// false to make it flash.
+func (r *Renderer) DrawSnakeDying(screen *ebiten.Image, sc *entities.SnakeController, visible int, headVisible bool) {
+	index := 0
+	for seg := sc.Head; seg != nil && index < visible; seg = seg.Next {
+		if index > 0 || headVisible {
+			part, angle := segmentSprite(seg)
+			r.SpriteManager.DrawSegment(screen, part, seg.Pos, angle*math.Pi/180, index)
+		}
+		index++
+	}
+}
+
+// drawSnakeSmooth draws the snake along a Catmull-Rom spline through the segment
+// centers. The head slides towards the next cell, the tail slides out of its cell,
+// and extra half-step body sprites round off the corners at bends.
+//
+// Segments that went through a portal or wrapped around the board are not next to
+// their neighbors, so the spline runs through an unbroken copy of the path rebuilt
+// from each segment's direction. Every sample is then shifted back by the offset of
+// the nearest real cell, making the body slide into one portal and out of the other.
+func (r *Renderer) drawSnakeSmooth(screen *ebiten.Image, sc *entities.SnakeController, progress float64) {
+	cell := float64(r.SpriteManager.CellSize)
+
+	// Path points in cell units, from the cell the head is moving into back to the tail.
+	head := sc.Head.Pos
+	next := head.Add(sc.UpcomingDir())
+	path := []point{{float64(next.X), float64(next.Y)}, {float64(head.X), float64(head.Y)}}
+	offsets := []point{{}, {}}
+	unbroken := head
+	count := 1
+	for seg := sc.Head; seg.Next != nil; seg = seg.Next {
+		unbroken = unbroken.Sub(seg.Dir)
+		actual := seg.Next.Pos
+		path = append(path, point{float64(unbroken.X), float64(unbroken.Y)})
+		offsets = append(offsets, point{float64(actual.X - unbroken.X), float64(actual.Y - unbroken.Y)})
+		count++
+	}
+	// shifted moves a spline sample at parameter u back onto the real board.
+	shifted := func(p point, u float64) point {
+		i := int(math.Round(u))
+		if i < 0 {
+			i = 0
+		}
+		if i >= len(offsets) {
+			i = len(offsets) - 1
 		}
+		return point{p.x + offsets[i].x, p.y + offsets[i].y}
+	}
+
+	// The tail stays put on a growing move, otherwise it follows the body.
+	tailShift := progress
+	if sc.Growing {
+		tailShift = 0
+	}
+
+	// Draw from the tail forward so the head ends up on top.
+	for i := count - 1; i >= 0; i-- {
+		u := float64(i) + 1 - progress
+		part := BodyHorizontal
+		switch {
+		case i == 0:
+			part = Head
+		case i == count-1:
+			part = Tail
+			u = float64(i) + 1 - tailShift
+		}
+
+		pos, tangent := splineAt(path, u)
+		pos = shifted(pos, u)
+		angle := math.Atan2(tangent.x, -tangent.y) // 0 = up, matching the sprite orientation
+		switch part {
+		case Tail:
+			angle += math.Pi
+		case BodyHorizontal:
+			angle -= math.Pi / 2
+		}
+		r.SpriteManager.DrawSegmentAt(screen, part, pos.x*cell, pos.y*cell, angle, i)
+
+		// Fill the gap towards the previous segment so curves look continuous.
+		if i > 0 {
+			mid, midTangent := splineAt(path, u-0.5)
+			mid = shifted(mid, u-0.5)
+			midAngle := math.Atan2(midTangent.x, -midTangent.y) - math.Pi/2
+			r.SpriteManager.DrawSegmentAt(screen, BodyHorizontal, mid.x*cell, mid.y*cell, midAngle, i)
+		}
+	}
+}
+
+// point is a position or direction in (fractional) grid cells.
+type point struct {
+	x, y float64
+}
+
+// splineAt samples a Catmull-Rom spline through pts at parameter u, where whole
+// numbers land exactly on the points. It returns the position and the tangent.
+func splineAt(pts []point, u float64) (point, point) {
+	k := int(math.Floor(u))
+	if k < 0 {
+		k = 0
+	}
+	if k > len(pts)-2 {
+		k = len(pts) - 2
+	}
+	t := u - float64(k)
+
+	p1, p2 := pts[k], pts[k+1]
+	p0 := point{2*p1.x - p2.x, 2*p1.y - p2.y}
+	if k > 0 {
+		p0 = pts[k-1]
+	}
+	p3 := point{2*p2.x - p1.x, 2*p2.y - p1.y}
+	if k+2 < len(pts) {
+		p3 = pts[k+2]
+	}
 
-		r.SpriteManager.DrawSegment(screen, spriteType, seg.Pos, angle*math.Pi/180)
+	catmull := func(a, b, c, d float64) (float64, float64) {
+		t2, t3 := t*t, t*t*t
+		v := 0.5 * (2*b + (c-a)*t + (2*a-5*b+4*c-d)*t2 + (3*b-a-3*c+d)*t3)
+		dv := 0.5 * ((c - a) + 2*(2*a-5*b+4*c-d)*t + 3*(3*b-a-3*c+d)*t2)
+		return v, dv
 	}
+	x, dx := catmull(p0.x, p1.x, p2.x, p3.x)
+	y, dy := catmull(p0.y, p1.y, p2.y, p3.y)
+	return point{x, y}, point{dx, dy}
 }
@@ -0,0 +1,110 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Skin describes how the snake is drawn: one image per SnakePart plus optional tinting.
+type Skin struct {
+	Name        string
+	Parts       map[SnakePart]string                             // asset path per part
+	Generate    func(part SnakePart, cellSize int) *ebiten.Image // used instead of Parts when set
+	Tint        color.Color                                      // optional tint applied to every segment
+	Pattern     func(index int) color.Color                      // optional per-segment tint, index 0 = head
+	UnlockScore int                                              // best score needed to unlock; 0 = always available
+}
+
+// Skins lists every skin in the order it appears in settings.
+var Skins = []*Skin{
+	{
+		Name: "Yellow",
+		Parts: map[SnakePart]string{
+			Head:           "assets/snake_yellow_head_16.png",
+			Tail:           "assets/snake_yellow_blob.png",
+			BodyVertical:   "assets/snake_yellow_blob.png",
+			BodyHorizontal: "assets/snake_yellow_blob.png",
+			Bend:           "assets/snake_yellow_blob.png",
+		},
+	},
+	{
+		Name: "Green",
+		Parts: map[SnakePart]string{
+			Head:           "assets/snake_head_upward.png",
+			Tail:           "assets/snake_tail.png",
+			BodyVertical:   "assets/snake_body_alt.png",
+			BodyHorizontal: "assets/snake_body_alt.png",
+			Bend:           "assets/snake_body_bend.png",
+		},
+		UnlockScore: 10,
+	},
+	{
+		Name: "Striped",
+		Parts: map[SnakePart]string{
+			Head:           "assets/snake_yellow_head_16.png",
+			Tail:           "assets/snake_yellow_blob.png",
+			BodyVertical:   "assets/snake_yellow_blob.png",
+			BodyHorizontal: "assets/snake_yellow_blob.png",
+			Bend:           "assets/snake_yellow_blob.png",
+		},
+		Pattern: func(index int) color.Color {
+			if index > 0 && index%2 == 0 {
+				return color.RGBA{R: 0x80, G: 0x50, B: 0x20, A: 0xff}
+			}
+			return nil
+		},
+		UnlockScore: 25,
+	},
+	{
+		Name:        "Pixel Retro",
+		Generate:    generateRetroPart,
+		UnlockScore: 50,
+	},
+}
+
+// SkinByName returns the skin with the given name, falling back to the first skin.
+func SkinByName(name string) *Skin {
+	for _, skin := range Skins {
+		if skin.Name == name {
+			return skin
+		}
+	}
+	return Skins[0]
+}
+
+// Unlocked reports whether the skin is available for the given best score.
+func (s *Skin) Unlocked(bestScore int) bool {
+	return bestScore >= s.UnlockScore
+}
+
+// SegmentTint returns the combined tint for the segment at index, or nil for none.
+func (s *Skin) SegmentTint(index int) color.Color {
+	if s.Pattern != nil {
+		if c := s.Pattern(index); c != nil {
+			return c
+		}
+	}
+	return s.Tint
+}
+
+// generateRetroPart draws flat green blocks in the style of old LCD snake games.
+func generateRetroPart(part SnakePart, cellSize int) *ebiten.Image {
+	img := ebiten.NewImage(cellSize, cellSize)
+	dark := color.RGBA{R: 0x0f, G: 0x38, B: 0x0f, A: 0xff}
+	light := color.RGBA{R: 0x30, G: 0x62, B: 0x30, A: 0xff}
+
+	inset := cellSize / 8
+	img.Fill(dark)
+	inner := img.SubImage(image.Rect(inset, inset, cellSize-inset, cellSize-inset)).(*ebiten.Image)
+	inner.Fill(light)
+
+	if part == Head {
+		// Two eye pixels near the top edge; the head sprite faces up before rotation.
+		eye := cellSize / 5
+		img.SubImage(image.Rect(inset*2, inset*2, inset*2+eye, inset*2+eye)).(*ebiten.Image).Fill(dark)
+		img.SubImage(image.Rect(cellSize-inset*2-eye, inset*2, cellSize-inset*2, inset*2+eye)).(*ebiten.Image).Fill(dark)
+	}
+	return img
+}
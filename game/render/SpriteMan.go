@@ -23,6 +23,8 @@ const (
 type SpriteManager struct {
 	Parts    map[SnakePart]*ebiten.Image
 	CellSize int
+	Skin     *Skin
+	cache    map[string]map[SnakePart]*ebiten.Image // loaded parts per skin name
 }
 
 func loadImageScaled(path string, width, height int) *ebiten.Image {
@@ -42,25 +44,46 @@ func scaleSprite(src *ebiten.Image, targetWidth, targetHeight int) *ebiten.Image
 }
 
 func NewSpriteManager(cellSize int) *SpriteManager {
-	sprites := map[SnakePart]*ebiten.Image{
-		Head:           loadImageScaled("assets/snake_yellow_head_16.png", cellSize, cellSize),
-		Tail:           loadImageScaled("assets/snake_yellow_blob.png", cellSize, cellSize),
-		BodyVertical:   loadImageScaled("assets/snake_yellow_blob.png", cellSize, cellSize),
-		BodyHorizontal: loadImageScaled("assets/snake_yellow_blob.png", cellSize, cellSize),
-		Bend:           loadImageScaled("assets/snake_yellow_blob.png", cellSize, cellSize),
+	s := &SpriteManager{
+		CellSize: cellSize,
+		cache:    make(map[string]map[SnakePart]*ebiten.Image),
 	}
+	s.SetSkin(Skins[0])
+	return s
+}
 
-	return &SpriteManager{
-		Parts:    sprites,
-		CellSize: cellSize,
+// SetSkin swaps the active skin. Parts are loaded once per skin and reused afterwards,
+// so switching skins mid-game is cheap.
+func (s *SpriteManager) SetSkin(skin *Skin) {
+	parts, ok := s.cache[skin.Name]
+	if !ok {
+		parts = make(map[SnakePart]*ebiten.Image)
+		for _, part := range []SnakePart{Head, Tail, BodyVertical, BodyHorizontal, Bend} {
+			if skin.Generate != nil {
+				parts[part] = skin.Generate(part, s.CellSize)
+			} else if path, ok := skin.Parts[part]; ok {
+				parts[part] = loadImageScaled(path, s.CellSize, s.CellSize)
+			}
+		}
+		s.cache[skin.Name] = parts
 	}
+	s.Skin = skin
+	s.Parts = parts
 }
 
 func (s *SpriteManager) ResolveSegmentSprite(tileType SnakePart) *ebiten.Image {
 	return s.Parts[tileType]
 }
 
-func (s *SpriteManager) DrawSegment(screen *ebiten.Image, spriteType SnakePart, pos image.Point, rotation float64) {
+// DrawSegment draws one snake part at a grid position. index is the segment's distance
+// from the head and drives the skin's per-segment pattern.
+func (s *SpriteManager) DrawSegment(screen *ebiten.Image, spriteType SnakePart, pos image.Point, rotation float64, index int) {
+	s.DrawSegmentAt(screen, spriteType, float64(pos.X*s.CellSize), float64(pos.Y*s.CellSize), rotation, index)
+}
+
+// DrawSegmentAt draws one snake part with its top-left corner at a pixel position,
+// used when segments slide between grid cells.
+func (s *SpriteManager) DrawSegmentAt(screen *ebiten.Image, spriteType SnakePart, x, y float64, rotation float64, index int) {
 	img := s.ResolveSegmentSprite(spriteType)
 	if img == nil {
 		return
@@ -81,7 +104,14 @@ func (s *SpriteManager) DrawSegment(screen *ebiten.Image, spriteType SnakePart,
 	}
 
 	// Translate to correct position
-	op.GeoM.Translate(float64(pos.X*s.CellSize), float64(pos.Y*s.CellSize))
+	op.GeoM.Translate(x, y)
+
+	// Apply skin tint
+	if s.Skin != nil {
+		if tint := s.Skin.SegmentTint(index); tint != nil {
+			op.ColorScale.ScaleWithColor(tint)
+		}
+	}
 
 	// Draw to screen
 	screen.DrawImage(img, op)
@@ -0,0 +1,291 @@
+package render
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"log"
+	"math"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Base font sizes in pixels for a 320px tall screen; they scale with the screen height.
+const (
+	FontSizeSmall = 8.0
+	FontSizeBody  = 11.0
+	FontSizeTitle = 22.0
+)
+
+// designHeight is the screen height the base font sizes were chosen for.
+const designHeight = 320.0
+
+// TextStyle controls how a piece of UI text is drawn.
+type TextStyle struct {
+	Size    float64     // base size, scaled with the screen
+	Bold    bool        // use the bold face
+	Color   color.Color // fill color; nil uses the UI text color
+	Align   text.Align  // horizontal alignment relative to x
+	Shadow  bool        // dark drop shadow one pixel down-right
+	Outline bool        // dark one-pixel outline on all sides
+}
+
+// fonts holds the parsed TrueType sources shared by every UIManager.
+type fonts struct {
+	regular *text.GoTextFaceSource
+	bold    *text.GoTextFaceSource
+}
+
+func loadFonts() fonts {
+	regular, err := text.NewGoTextFaceSource(bytes.NewReader(goregular.TTF))
+	if err != nil {
+		log.Fatalf("Failed to load regular font: %v", err)
+	}
+	bold, err := text.NewGoTextFaceSource(bytes.NewReader(gobold.TTF))
+	if err != nil {
+		log.Fatalf("Failed to load bold font: %v", err)
+	}
+	return fonts{regular: regular, bold: bold}
+}
+
+// face returns a face for the style, scaled to the current screen height.
+func (ui *UIManager) face(style TextStyle) *text.GoTextFace {
+	src := ui.fonts.regular
+	if style.Bold {
+		src = ui.fonts.bold
+	}
+	return &text.GoTextFace{Source: src, Size: style.Size * ui.scale}
+}
+
+// MeasureText returns the width and height of s in pixels when drawn with style.
+func (ui *UIManager) MeasureText(s string, style TextStyle) (float64, float64) {
+	face := ui.face(style)
+	return text.Measure(s, face, face.Metrics().HLineGap+face.Metrics().HAscent+face.Metrics().HDescent)
+}
+
+// textOp is a piece of text queued in screen coordinates, waiting to be drawn
+// onto the final screen.
+type textOp struct {
+	s     string
+	face  *text.GoTextFace // sized for the screen; rescaled for the final screen
+	x, y  float64
+	align text.Align
+	color ebiten.ColorScale
+}
+
+// TextFrame is the text queued while drawing a frame, kept by the screen manager
+// to replay it in a transition.
+type TextFrame struct {
+	ops []textOp
+}
+
+// DrawText draws s with its top edge at y, aligned horizontally around x. Text
+// is not drawn onto screen itself, which Ebiten scales up to the window, but
+// queued and drawn over it at the window's resolution by DrawFinalScreen so it
+// stays sharp.
+func (ui *UIManager) DrawText(_ *ebiten.Image, s string, x, y float64, style TextStyle) {
+	face := ui.face(style)
+	fill := style.Color
+	if fill == nil {
+		fill = ui.TextColor
+	}
+
+	draw := func(dx, dy float64, c color.Color) {
+		op := textOp{s: s, face: face, x: x + dx, y: y + dy, align: style.Align}
+		op.color.ScaleWithColor(c)
+		ui.text = append(ui.text, op)
+	}
+
+	// Shadows and outlines take the fill's alpha so fading text fades as a whole.
+	_, _, _, a := fill.RGBA()
+	dark := color.RGBA{A: uint8(a >> 8)}
+	if style.Outline {
+		for _, d := range [][2]float64{{-1, 0}, {1, 0}, {0, -1}, {0, 1}, {-1, -1}, {1, -1}, {-1, 1}, {1, 1}} {
+			draw(d[0], d[1], dark)
+		}
+	} else if style.Shadow {
+		draw(1, 1, dark)
+	}
+	draw(0, 0, fill)
+}
+
+// ClearText drops the text queued for the last frame; call it before drawing a new one.
+func (ui *UIManager) ClearText() {
+	ui.text = ui.text[:0]
+}
+
+// Text returns a copy of the text queued so far this frame.
+func (ui *UIManager) Text() TextFrame {
+	return TextFrame{ops: slices.Clone(ui.text)}
+}
+
+// TakeText returns the text queued so far this frame and removes it from the queue.
+func (ui *UIManager) TakeText() TextFrame {
+	f := ui.Text()
+	ui.ClearText()
+	return f
+}
+
+// QueueText adds the text of f moved by geoM and faded by alpha, as when a frame
+// is drawn with those options in a transition.
+func (ui *UIManager) QueueText(f TextFrame, geoM ebiten.GeoM, alpha float32) {
+	for _, op := range f.ops {
+		op.x, op.y = geoM.Apply(op.x, op.y)
+		op.color.ScaleAlpha(alpha)
+		ui.text = append(ui.text, op)
+	}
+}
+
+// dimText darkens the queued text as if a black layer of the given opacity had
+// been drawn over it.
+func (ui *UIManager) dimText(opacity float32) {
+	for i := range ui.text {
+		ui.text[i].color.Scale(1-opacity, 1-opacity, 1-opacity, 1)
+	}
+}
+
+// screenShaderSrc scales pixel art up by a non-integer factor without blurring it,
+// blending only the edges between source pixels. It is the shader Ebiten uses for
+// the final screen when the game does not draw it itself.
+var screenShaderSrc = []byte(`//kage:unit pixels
+
+package main
+
+func Fragment(dstPos vec4, srcPos vec2) vec4 {
+	scale := imageDstSize()/imageSrc0Size()
+	p0 := srcPos - 1/2.0/scale
+	p1 := srcPos + 1/2.0/scale
+
+	c0 := imageSrc0UnsafeAt(p0)
+	c1 := imageSrc0UnsafeAt(vec2(p1.x, p0.y))
+	c2 := imageSrc0UnsafeAt(vec2(p0.x, p1.y))
+	c3 := imageSrc0UnsafeAt(p1)
+
+	rate := clamp(fract(p1)*scale, 0, 1)
+	return mix(mix(c0, c1, rate.x), mix(c2, c3, rate.x), rate.y)
+}
+`)
+
+// DrawFinalScreen scales the frame up to the window the way Ebiten does by
+// default, then draws the queued text on top. The final screen is the window's
+// size in device pixels, the window size times the monitor's device scale
+// factor, so the fonts are rendered at that resolution instead of being scaled up.
+func (ui *UIManager) DrawFinalScreen(screen ebiten.FinalScreen, offscreen *ebiten.Image, geoM ebiten.GeoM) {
+	scale := geoM.Element(0, 0)
+	switch {
+	case math.Floor(scale) == scale:
+		op := &ebiten.DrawImageOptions{GeoM: geoM}
+		screen.DrawImage(offscreen, op)
+	case scale < 1:
+		op := &ebiten.DrawImageOptions{GeoM: geoM, Filter: ebiten.FilterLinear}
+		screen.DrawImage(offscreen, op)
+	default:
+		if ui.screenShader == nil {
+			shader, err := ebiten.NewShader(screenShaderSrc)
+			if err != nil {
+				log.Fatalf("Failed to compile screen shader: %v", err)
+			}
+			ui.screenShader = shader
+		}
+		op := &ebiten.DrawRectShaderOptions{GeoM: geoM}
+		op.Images[0] = offscreen
+		size := offscreen.Bounds().Size()
+		screen.DrawRectShader(size.X, size.Y, ui.screenShader, op)
+	}
+
+	bounds := screen.Bounds()
+	if ui.textLayer == nil || ui.textLayer.Bounds() != bounds {
+		ui.textLayer = ebiten.NewImage(bounds.Dx(), bounds.Dy())
+	}
+	ui.textLayer.Clear()
+	for _, t := range ui.text {
+		face := &text.GoTextFace{Source: t.face.Source, Size: t.face.Size * scale}
+		x, y := geoM.Apply(t.x, t.y)
+		op := &text.DrawOptions{}
+		op.PrimaryAlign = t.align
+		op.GeoM.Translate(x, y)
+		op.ColorScale = t.color
+		text.Draw(ui.textLayer, t.s, face, op)
+	}
+	screen.DrawImage(ui.textLayer, nil)
+}
+
+// DrawMenu lays out a menu's title, optional info lines and items centered horizontally
+// on the screen, starting at top. The focused item is highlighted with the accent
+// color. Item bounds are remembered on the menu for mouse input.
+func (ui *UIManager) DrawMenu(screen *ebiten.Image, screenWidth int, top float64, menu *Menu, info []string) {
+	centerX := float64(screenWidth) / 2
+	y := top
+
+	if menu.Title != "" {
+		style := TextStyle{Size: FontSizeTitle, Bold: true, Align: text.AlignCenter, Outline: true}
+		ui.DrawText(screen, menu.Title, centerX, y, style)
+		_, h := ui.MeasureText(menu.Title, style)
+		y += h + 8*ui.scale
+	}
+
+	infoStyle := TextStyle{Size: FontSizeBody, Align: text.AlignCenter, Shadow: true}
+	for _, line := range info {
+		ui.DrawText(screen, line, centerX, y, infoStyle)
+		_, h := ui.MeasureText(line, infoStyle)
+		y += h + 2*ui.scale
+	}
+	if len(info) > 0 {
+		y += 8 * ui.scale
+	}
+
+	menu.rects = make(map[*MenuItem]image.Rectangle, len(menu.Items))
+	for i, item := range menu.Items {
+		if !item.visible() {
+			continue
+		}
+		label := item.Label
+		if item.Value != nil {
+			switch item.Kind {
+			case MenuLabel:
+				label = item.Value()
+			case MenuText:
+				label += ": " + item.Value()
+			default:
+				label += ": < " + item.Value() + " >"
+			}
+		}
+
+		style := TextStyle{Size: FontSizeBody, Align: text.AlignCenter, Shadow: true}
+		switch {
+		case item.Kind == MenuLabel:
+			style.Size = FontSizeSmall
+		case i == menu.Selected:
+			style.Bold = true
+			style.Color = ui.AccentColor
+			label = "> " + label + " <"
+		}
+
+		w, h := ui.MeasureText(label, style)
+		rowH := h
+		if item.Kind == MenuSlider && item.Level != nil {
+			rowH += ui.drawSlider(screen, centerX, y+h+2*ui.scale, item.Level(), style.Color)
+		}
+		ui.DrawText(screen, label, centerX, y, style)
+
+		menu.rects[item] = image.Rect(int(centerX-w/2), int(y), int(centerX+w/2), int(y+rowH))
+		y += rowH + 10*ui.scale
+	}
+}
+
+// drawSlider draws a horizontal bar filled to level (0..1) centered on x, returning its height.
+func (ui *UIManager) drawSlider(screen *ebiten.Image, x, y, level float64, fill color.Color) float64 {
+	if fill == nil {
+		fill = ui.TextColor
+	}
+	w, h := 80*ui.scale, 4*ui.scale
+	left := x - w/2
+	vector.DrawFilledRect(screen, float32(left), float32(y), float32(w), float32(h), color.RGBA{A: 0x80}, false)
+	vector.DrawFilledRect(screen, float32(left), float32(y), float32(w*level), float32(h), fill, false)
+	return h + 2*ui.scale
+}
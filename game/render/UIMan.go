@@ -2,84 +2,288 @@ package render
 
 import (
 	"fmt"
+	"image/color"
+	"math"
+	"slices"
+	"time"
 
 	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 )
 
+// ScoreLine is one entry of a score breakdown.
+type ScoreLine struct {
+	Label  string
+	Points int
+}
+
+// RunStats summarizes a finished run for the game-over screen.
+type RunStats struct {
+	Score     int
+	Level     int
+	Length    int
+	BestScore int
+	Breakdown []ScoreLine // where the score came from
+	Note      string      // optional remark, e.g. why the score was not recorded
+}
+
+// HUDStats is everything shown in the in-game HUD bar.
+type HUDStats struct {
+	Score         int
+	BestScore     int
+	Level         int
+	LevelProgress float64 // 0..1 towards the next level
+	Length        int
+	Elapsed       time.Duration
+	MovesPerSec   float64
+	Timed         bool          // show TimeLeft counting down instead of Elapsed
+	TimeLeft      time.Duration // time remaining in timed modes
+}
+
+// HUDTimer shows a running timed effect, such as a power-up, in the HUD.
+type HUDTimer struct {
+	Label     string
+	Icon      *ebiten.Image // optional icon drawn before the bar
+	Stacks    int           // shown as "xN" when above 1
+	Remaining float64       // 0..1 of the duration left
+}
+
+// EditorStats is everything shown in the HUD bar of the level editor.
+type EditorStats struct {
+	Tool          string
+	Name          string
+	Width, Height int
+	Status        string // last message, e.g. a save result; replaces the key hints
+}
+
+// Heatmap is a square grid of counts, drawn with each cell shaded by its count.
+type Heatmap struct {
+	Size   int   // cells per side
+	Counts []int // Size*Size counts, row by row
+}
+
 // UIManager handles overlay rendering like score, pause, and game over prompts.
-type UIManager struct{}
+type UIManager struct {
+	TextColor   color.Color // default text fill, usually from the theme palette
+	AccentColor color.Color // highlight for selected menu items
+	fonts       fonts
+	scale       float64 // font scale relative to the design height
+
+	text         []textOp      // text queued this frame, drawn by DrawFinalScreen
+	textLayer    *ebiten.Image // the queued text rendered at the final screen's size
+	screenShader *ebiten.Shader
+}
 
 // NewUIManager creates a new instance of UIManager.
 func NewUIManager() *UIManager {
-	return &UIManager{}
+	return &UIManager{
+		TextColor:   color.White,
+		AccentColor: color.RGBA{R: 0xf2, G: 0xc2, B: 0x30, A: 0xff},
+		fonts:       loadFonts(),
+		scale:       1,
+	}
 }
 
-// DrawStatus draws the in-game score and level.
-func (ui *UIManager) DrawStatus(screen *ebiten.Image, score, level int) {
-	text := fmt.Sprintf("Score: %d  Level: %d", score, level)
-	ebitenutil.DebugPrintAt(screen, text, 5, 5)
+// SetScreenSize rescales fonts for a screen of the given logical size. DrawFinalScreen
+// scales them again from there to the window.
+func (ui *UIManager) SetScreenSize(_, screenHeight int) {
+	ui.scale = float64(screenHeight) / designHeight
 }
 
-// DrawPauseOverlay draws a "PAUSED" message in the center of the screen.
-func (ui *UIManager) DrawPauseOverlay(screen *ebiten.Image, screenWidth, screenHeight int) {
-	text := "PAUSED"
-	ebitenutil.DebugPrintAt(screen, text, screenWidth/2-30, screenHeight/2-20)
-}
+// DrawHUD draws the in-game stats into a bar of the given size at the top of the screen.
+func (ui *UIManager) DrawHUD(screen *ebiten.Image, width, height int, stats HUDStats) {
+	pad := 4 * ui.scale
+	small := TextStyle{Size: FontSizeSmall, Outline: true}
+	body := TextStyle{Size: FontSizeBody, Bold: true, Outline: true}
+	row2 := float64(height) / 2
 
-// DrawGameOverOverlay draws the game over screen with selectable options.
-func (ui *UIManager) DrawGameOverOverlay(screen *ebiten.Image, screenWidth, screenHeight int, selected int) {
-	centerX := screenWidth / 2
-	centerY := screenHeight / 2
+	// Left: score and the score to beat.
+	ui.DrawText(screen, fmt.Sprintf("Score %d", stats.Score), pad, pad/2, body)
+	best := fmt.Sprintf("Best %d", stats.BestScore)
+	if stats.Score > stats.BestScore {
+		best = "New best!"
+	}
+	ui.DrawText(screen, best, pad, row2, small)
 
-	// Game Over
-	ebitenutil.DebugPrintAt(screen, "GAME OVER", centerX-40, centerY-40)
+	// Middle: level with progress towards the next one.
+	centerX := float64(width) / 2
+	ui.DrawText(screen, fmt.Sprintf("Level %d", stats.Level), centerX, pad/2, TextStyle{Size: FontSizeBody, Bold: true, Outline: true, Align: text.AlignCenter})
+	barW, barH := float32(width)/4, float32(3*ui.scale)
+	barX, barY := float32(centerX)-barW/2, float32(row2+pad)
+	vector.DrawFilledRect(screen, barX, barY, barW, barH, color.RGBA{A: 0x80}, false)
+	vector.DrawFilledRect(screen, barX, barY, barW*float32(stats.LevelProgress), barH, ui.AccentColor, false)
 
-	// Options
-	options := []string{"Play Again", "Main Menu", "Exit Game"}
-	menuStartY := centerY + 8
-	for i, item := range options {
-		prefix := "  "
-		if i == selected {
-			prefix = "> "
+	// Right: time, length and speed.
+	right := TextStyle{Size: FontSizeSmall, Outline: true, Align: text.AlignEnd}
+	clock := TextStyle{Size: FontSizeBody, Bold: true, Outline: true, Align: text.AlignEnd}
+	shown := stats.Elapsed
+	if stats.Timed {
+		// Round up so the timer reads 0:00 only once time has actually run out.
+		shown = stats.TimeLeft + time.Second - 1
+		if stats.TimeLeft <= 10*time.Second {
+			clock.Color = ui.AccentColor
 		}
-		text := prefix + item
-		charWidth := 7 // estimated width per char in debug font
-		textWidth := len(text) * charWidth
-		x := centerX - textWidth/2
-		y := menuStartY + i*30
-		ebitenutil.DebugPrintAt(screen, text, x, y)
 	}
+	minutes := int(shown.Minutes())
+	seconds := int(shown.Seconds()) % 60
+	ui.DrawText(screen, fmt.Sprintf("%02d:%02d", minutes, seconds), float64(width)-pad, pad/2, clock)
+	ui.DrawText(screen, fmt.Sprintf("Len %d  %.1f/s", stats.Length, stats.MovesPerSec), float64(width)-pad, row2, right)
 }
 
-// DrawTitleScreen draws the title screen with selectable menu tiles.
-func (ui *UIManager) DrawTitleScreen(screen *ebiten.Image, screenWidth, screenHeight int, selected int) {
-	title := "SNAKE GAME"
-	menu := []string{"Play Game", "Settings"}
+// DrawTimers draws a row of effect timers starting at (x, y), each an icon of the
+// given size with a draining bar underneath.
+func (ui *UIManager) DrawTimers(screen *ebiten.Image, x, y float64, size int, timers []HUDTimer) {
+	small := TextStyle{Size: FontSizeSmall, Outline: true}
+	barH := float32(2 * ui.scale)
+	for _, t := range timers {
+		if t.Icon != nil {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(float64(size)/float64(t.Icon.Bounds().Dx()), float64(size)/float64(t.Icon.Bounds().Dy()))
+			op.GeoM.Translate(x, y)
+			screen.DrawImage(t.Icon, op)
+		} else {
+			ui.DrawText(screen, t.Label[:1], x, y, small)
+		}
+		barY := float32(y) + float32(size) + 1
+		vector.DrawFilledRect(screen, float32(x), barY, float32(size), barH, color.RGBA{A: 0x80}, false)
+		vector.DrawFilledRect(screen, float32(x), barY, float32(size)*float32(t.Remaining), barH, ui.AccentColor, false)
+		if t.Stacks > 1 {
+			ui.DrawText(screen, fmt.Sprintf("x%d", t.Stacks), x+float64(size), y, small)
+			x += float64(size) / 2
+		}
+		x += float64(size) * 1.5
+	}
+}
 
-	// Draw title centered at top
-	titleX := screenWidth/2 - len(title)*7/2
-	titleY := screenHeight / 4
-	ebitenutil.DebugPrintAt(screen, title, titleX, titleY)
+// DrawEditorHUD draws the level editor's tool and map details into the HUD bar.
+func (ui *UIManager) DrawEditorHUD(screen *ebiten.Image, width, height int, stats EditorStats) {
+	pad := 4 * ui.scale
+	small := TextStyle{Size: FontSizeSmall, Outline: true}
+	body := TextStyle{Size: FontSizeBody, Bold: true, Outline: true}
+	row2 := float64(height) / 2
 
-	// Draw menu options
-	menuStartY := titleY + 40
-	for i, item := range menu {
-		prefix := "  "
-		if i == selected {
-			prefix = "> "
-		}
-		text := prefix + item
-		textX := screenWidth/2 - len(text)*7/2
-		textY := menuStartY + i*30
-		ebitenutil.DebugPrintAt(screen, text, textX, textY)
+	ui.DrawText(screen, "Tool: "+stats.Tool, pad, pad/2, body)
+	hint := "Tab tool  Right-click erase  Esc menu"
+	if stats.Status != "" {
+		hint = stats.Status
+		small.Color = ui.AccentColor
+	}
+	ui.DrawText(screen, hint, pad, row2, small)
+
+	name := stats.Name
+	if name == "" {
+		name = "Untitled"
+	}
+	ui.DrawText(screen, name, float64(width)-pad, pad/2, TextStyle{Size: FontSizeBody, Bold: true, Outline: true, Align: text.AlignEnd})
+	ui.DrawText(screen, fmt.Sprintf("%dx%d", stats.Width, stats.Height), float64(width)-pad, row2, TextStyle{Size: FontSizeSmall, Outline: true, Align: text.AlignEnd})
+}
+
+// DrawEditorMenu draws the level editor's menu over the board.
+func (ui *UIManager) DrawEditorMenu(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu) {
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/10, menu, nil)
+}
+
+// DrawPauseMenu draws the pause menu in the middle of the screen.
+func (ui *UIManager) DrawPauseMenu(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu) {
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/4, menu, nil)
+}
+
+// DrawCountdown draws a large countdown number in the center of the screen.
+func (ui *UIManager) DrawCountdown(screen *ebiten.Image, screenWidth, screenHeight int, remaining int) {
+	msg := fmt.Sprintf("%d", remaining)
+	style := TextStyle{Size: FontSizeTitle * 2, Bold: true, Align: text.AlignCenter, Outline: true}
+	_, h := ui.MeasureText(msg, style)
+	ui.DrawText(screen, msg, float64(screenWidth)/2, (float64(screenHeight)-h)/2, style)
+}
+
+// DrawRewind marks the board as rewinding with a banner across its middle.
+func (ui *UIManager) DrawRewind(screen *ebiten.Image, screenWidth, screenHeight int) {
+	style := TextStyle{Size: FontSizeTitle, Bold: true, Align: text.AlignCenter, Outline: true, Color: ui.AccentColor}
+	_, h := ui.MeasureText("<< REWIND", style)
+	ui.DrawText(screen, "<< REWIND", float64(screenWidth)/2, (float64(screenHeight)-h)/2, style)
+}
+
+// DrawDim darkens everything drawn so far, text included, used behind menus shown
+// over the board.
+func (ui *UIManager) DrawDim(screen *ebiten.Image) {
+	const dim = 0xa0
+	w, h := screen.Bounds().Dx(), screen.Bounds().Dy()
+	vector.DrawFilledRect(screen, 0, 0, float32(w), float32(h), color.RGBA{A: dim}, false)
+	ui.dimText(dim / 255.0)
+}
+
+// DrawGameOverOverlay draws the game over menu along with the final stats.
+func (ui *UIManager) DrawGameOverOverlay(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu, stats RunStats) {
+	info := []string{
+		fmt.Sprintf("Score: %d", stats.Score),
+		fmt.Sprintf("Level: %d  Length: %d", stats.Level, stats.Length),
+		fmt.Sprintf("Best: %d", stats.BestScore),
+	}
+	for _, line := range stats.Breakdown {
+		info = append(info, fmt.Sprintf("%s  +%d", line.Label, line.Points))
 	}
+	if stats.Note != "" {
+		info = append(info, stats.Note)
+	}
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/5, menu, info)
+}
+
+// DrawTitleScreen draws the title screen menu.
+func (ui *UIManager) DrawTitleScreen(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu) {
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/4, menu, nil)
 }
 
-// DrawSettingsScreen draws a placeholder for the settings screen.
-func (ui *UIManager) DrawSettingsScreen(screen *ebiten.Image, screenWidth, screenHeight int) {
-	msg := "Settings - Coming Soon"
-	msgX := screenWidth/2 - len(msg)*7/2
-	msgY := screenHeight / 2
-	ebitenutil.DebugPrintAt(screen, msg, msgX, msgY)
+// DrawSettingsScreen draws the settings menu.
+func (ui *UIManager) DrawSettingsScreen(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu) {
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/8, menu, nil)
+}
+
+// DrawHighScores draws the high score table above its menu.
+func (ui *UIManager) DrawHighScores(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu, scores []int) {
+	info := make([]string, 0, len(scores))
+	for i, score := range scores {
+		info = append(info, fmt.Sprintf("%2d.  %d", i+1, score))
+	}
+	if len(info) == 0 {
+		info = append(info, "No scores yet")
+	}
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/10, menu, info)
+}
+
+// DrawStatistics draws the statistics menu with the current page's lines and, when
+// given, a death heatmap filling the space below the menu.
+func (ui *UIManager) DrawStatistics(screen *ebiten.Image, screenWidth, screenHeight int, menu *Menu, info []string, heat *Heatmap) {
+	ui.DrawMenu(screen, screenWidth, float64(screenHeight)/12, menu, info)
+	if heat == nil || heat.Size <= 0 || len(heat.Counts) != heat.Size*heat.Size {
+		return
+	}
+
+	bottom := 0
+	for _, r := range menu.rects {
+		bottom = max(bottom, r.Max.Y)
+	}
+	pad := 6 * ui.scale
+	side := min(float64(screenHeight)-float64(bottom)-2*pad, float64(screenWidth)-2*pad)
+	cell := float32(math.Floor(side / float64(heat.Size)))
+	if cell < 1 {
+		return
+	}
+	left := (float32(screenWidth) - cell*float32(heat.Size)) / 2
+	top := float32(bottom) + float32(pad)
+
+	most := slices.Max(heat.Counts)
+	r, g, b, _ := ui.AccentColor.RGBA()
+	for i, count := range heat.Counts {
+		x := left + float32(i%heat.Size)*cell
+		y := top + float32(i/heat.Size)*cell
+		vector.DrawFilledRect(screen, x, y, cell-1, cell-1, color.RGBA{A: 0x60}, false)
+		if count == 0 {
+			continue
+		}
+		// Colors are premultiplied, so the accent is scaled as a whole to fade it.
+		f := 0.25 + 0.75*float64(count)/float64(most)
+		shade := color.RGBA64{uint16(float64(r) * f), uint16(float64(g) * f), uint16(float64(b) * f), uint16(0xffff * f)}
+		vector.DrawFilledRect(screen, x, y, cell-1, cell-1, shade, false)
+	}
 }
@@ -1,48 +1,185 @@
 package ui
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
 	"image"
+	"image/color"
 	_ "image/png"
 	"math"
 	"os"
+	"path/filepath"
+	"sort"
 )
 
-var (
-	grassTile   *ebiten.Image
-	vineSide    *ebiten.Image
-	vineCorner  *ebiten.Image
-	appleSprite *ebiten.Image
-)
+// Palette holds the colors a theme uses for menus and overlays.
+type Palette struct {
+	Background color.RGBA
+	Text       color.RGBA
+	Accent     color.RGBA
+}
+
+// Theme is a set of board graphics, colors and music loaded from a theme manifest.
+type Theme struct {
+	Name         string
+	Background   *ebiten.Image
+	BorderSide   *ebiten.Image
+	BorderCorner *ebiten.Image
+	Food         map[string]*ebiten.Image // food sprites keyed by kind, e.g. "apple"
+	PowerUps     map[string]*ebiten.Image // power-up sprites keyed by kind, e.g. "shield"
+	Hazard       *ebiten.Image            // obstacle and shrunk wall tile; optional
+	Movers       map[string]*ebiten.Image // enemy sprites keyed by kind, e.g. "patrol"
+	Portal       *ebiten.Image            // portal tile, tinted per pair; optional
+	Palette      Palette
+	Music        string // path to the looping background track
+}
+
+// themeManifest mirrors the JSON layout of a theme file in game/ui/themes/.
+type themeManifest struct {
+	Name         string            `json:"name"`
+	Background   string            `json:"background"`
+	BorderSide   string            `json:"border_side"`
+	BorderCorner string            `json:"border_corner"`
+	Food         map[string]string `json:"food"`
+	PowerUps     map[string]string `json:"powerups"`
+	Hazard       string            `json:"hazard"`
+	Movers       map[string]string `json:"movers"`
+	Portal       string            `json:"portal"`
+	Palette      struct {
+		Background string `json:"background"`
+		Text       string `json:"text"`
+		Accent     string `json:"accent"`
+	} `json:"palette"`
+	Music string `json:"music"`
+}
+
+// LoadThemes loads every *.json theme manifest in dir, sorted by name.
+func LoadThemes(dir string) ([]*Theme, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	themes := make([]*Theme, 0, len(paths))
+	for _, path := range paths {
+		theme, err := LoadTheme(path)
+		if err != nil {
+			return nil, err
+		}
+		themes = append(themes, theme)
+	}
+	if len(themes) == 0 {
+		return nil, fmt.Errorf("no themes found in %s", dir)
+	}
+	sort.Slice(themes, func(i, j int) bool { return themes[i].Name < themes[j].Name })
+	return themes, nil
+}
+
+// LoadTheme loads a single theme manifest and all the images it references.
+func LoadTheme(path string) (*Theme, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m themeManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse theme %s: %w", path, err)
+	}
 
-// LoadTheme loads all UI-related sprites into memory.
-// Place the image files in game/ui/assets/ with correct names.
-func LoadTheme() error {
-	grassTile = loadImage("game/ui/assets/tile_pebble.png")
-	vineSide = loadImage("game/ui/assets/border_side_vine.png")
-	vineCorner = loadImage("game/ui/assets/border_corner_vine.png")
-	appleSprite = loadImage("game/ui/assets/apple_sprite.png")
-	return nil
+	t := &Theme{
+		Name:     m.Name,
+		Music:    m.Music,
+		Food:     make(map[string]*ebiten.Image),
+		PowerUps: make(map[string]*ebiten.Image),
+		Movers:   make(map[string]*ebiten.Image),
+	}
+	if t.Background, err = loadImage(m.Background); err != nil {
+		return nil, err
+	}
+	if t.BorderSide, err = loadImage(m.BorderSide); err != nil {
+		return nil, err
+	}
+	if t.BorderCorner, err = loadImage(m.BorderCorner); err != nil {
+		return nil, err
+	}
+	for kind, foodPath := range m.Food {
+		if t.Food[kind], err = loadImage(foodPath); err != nil {
+			return nil, err
+		}
+	}
+	if m.Hazard != "" {
+		if t.Hazard, err = loadImage(m.Hazard); err != nil {
+			return nil, err
+		}
+	}
+	for kind, powerUpPath := range m.PowerUps {
+		if t.PowerUps[kind], err = loadImage(powerUpPath); err != nil {
+			return nil, err
+		}
+	}
+	if m.Portal != "" {
+		if t.Portal, err = loadImage(m.Portal); err != nil {
+			return nil, err
+		}
+	}
+	for kind, moverPath := range m.Movers {
+		if t.Movers[kind], err = loadImage(moverPath); err != nil {
+			return nil, err
+		}
+	}
+	if t.Palette.Background, err = parseHexColor(m.Palette.Background); err != nil {
+		return nil, err
+	}
+	if t.Palette.Text, err = parseHexColor(m.Palette.Text); err != nil {
+		return nil, err
+	}
+	if t.Palette.Accent, err = parseHexColor(m.Palette.Accent); err != nil {
+		return nil, err
+	}
+	return t, nil
 }
 
-func loadImage(path string) *ebiten.Image {
+// ThemeByName returns the theme with the given name, falling back to the first one.
+func ThemeByName(themes []*Theme, name string) *Theme {
+	for _, t := range themes {
+		if t.Name == name {
+			return t
+		}
+	}
+	return themes[0]
+}
+
+func loadImage(path string) (*ebiten.Image, error) {
 	f, err := os.Open(path)
 	if err != nil {
-		panic("Failed to load image: " + path)
+		return nil, fmt.Errorf("failed to load image %s: %w", path, err)
 	}
 	defer f.Close()
 
 	img, _, err := image.Decode(f)
 	if err != nil {
-		panic("Failed to decode image: " + path)
+		return nil, fmt.Errorf("failed to decode image %s: %w", path, err)
 	}
 
-	return ebiten.NewImageFromImage(img)
+	return ebiten.NewImageFromImage(img), nil
 }
 
-// DrawBackground tiles the grass tile to fill the entire screen.
-func DrawBackground(screen *ebiten.Image, screenWidth, screenHeight int, cellSize int) {
-	tileW, tileH := grassTile.Bounds().Dx(), grassTile.Bounds().Dy()
+// parseHexColor parses colors written as "#rrggbb". An empty string yields opaque black.
+func parseHexColor(s string) (color.RGBA, error) {
+	c := color.RGBA{A: 0xff}
+	if s == "" {
+		return c, nil
+	}
+	if _, err := fmt.Sscanf(s, "#%02x%02x%02x", &c.R, &c.G, &c.B); err != nil {
+		return c, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return c, nil
+}
+
+// DrawBackground tiles the background tile to fill the entire screen.
+func (t *Theme) DrawBackground(screen *ebiten.Image, screenWidth, screenHeight int, cellSize int) {
+	tileW, tileH := t.Background.Bounds().Dx(), t.Background.Bounds().Dy()
 	scaleX := float64(cellSize) / float64(tileW)
 	scaleY := float64(cellSize) / float64(tileH)
 
@@ -51,14 +188,14 @@ func DrawBackground(screen *ebiten.Image, screenWidth, screenHeight int, cellSiz
 			op := &ebiten.DrawImageOptions{}
 			op.GeoM.Scale(scaleX, scaleY)
 			op.GeoM.Translate(float64(x), float64(y))
-			screen.DrawImage(grassTile, op)
+			screen.DrawImage(t.Background, op)
 		}
 	}
 }
 
 // DrawBorder draws a decorative vine border around the game screen.
-func DrawBorder(screen *ebiten.Image, screenWidth, screenHeight int, cellSize int) {
-	tileH := vineSide.Bounds().Dy()
+func (t *Theme) DrawBorder(screen *ebiten.Image, screenWidth, screenHeight int, cellSize int) {
+	tileH := t.BorderSide.Bounds().Dy()
 	scale := float64(cellSize) / float64(tileH)
 
 	tilesX := screenWidth / cellSize
@@ -73,14 +210,14 @@ func DrawBorder(screen *ebiten.Image, screenWidth, screenHeight int, cellSize in
 		topOp.GeoM.Scale(scale, scale)
 		topOp.GeoM.Rotate(math.Pi / 2)
 		topOp.GeoM.Translate(x+float64(cellSize), 0)
-		screen.DrawImage(vineSide, topOp)
+		screen.DrawImage(t.BorderSide, topOp)
 
 		// Bottom (rotate -90°)
 		bottomOp := &ebiten.DrawImageOptions{}
 		bottomOp.GeoM.Scale(scale, scale)
 		bottomOp.GeoM.Rotate(-math.Pi / 2)
 		bottomOp.GeoM.Translate(x, float64(screenHeight))
-		screen.DrawImage(vineSide, bottomOp)
+		screen.DrawImage(t.BorderSide, bottomOp)
 	}
 
 	// Left & Right sides
@@ -91,14 +228,14 @@ func DrawBorder(screen *ebiten.Image, screenWidth, screenHeight int, cellSize in
 		leftOp := &ebiten.DrawImageOptions{}
 		leftOp.GeoM.Scale(scale, scale)
 		leftOp.GeoM.Translate(0, y)
-		screen.DrawImage(vineSide, leftOp)
+		screen.DrawImage(t.BorderSide, leftOp)
 
 		// Right (rotate 180°)
 		rightOp := &ebiten.DrawImageOptions{}
 		rightOp.GeoM.Scale(scale, scale)
 		rightOp.GeoM.Rotate(math.Pi)
 		rightOp.GeoM.Translate(float64(screenWidth), y+float64(cellSize))
-		screen.DrawImage(vineSide, rightOp)
+		screen.DrawImage(t.BorderSide, rightOp)
 	}
 
 	// Four corners
@@ -106,38 +243,113 @@ func DrawBorder(screen *ebiten.Image, screenWidth, screenHeight int, cellSize in
 	tlOp := &ebiten.DrawImageOptions{}
 	tlOp.GeoM.Scale(scale, scale)
 	tlOp.GeoM.Translate(0, 0)
-	screen.DrawImage(vineCorner, tlOp)
+	screen.DrawImage(t.BorderCorner, tlOp)
 
 	// Top-right (rotate 90°)
 	trOp := &ebiten.DrawImageOptions{}
 	trOp.GeoM.Scale(scale, scale)
 	trOp.GeoM.Rotate(math.Pi / 2)
 	trOp.GeoM.Translate(float64(screenWidth), 0)
-	screen.DrawImage(vineCorner, trOp)
+	screen.DrawImage(t.BorderCorner, trOp)
 
 	// Bottom-right (rotate 180°)
 	brOp := &ebiten.DrawImageOptions{}
 	brOp.GeoM.Scale(scale, scale)
 	brOp.GeoM.Rotate(math.Pi)
 	brOp.GeoM.Translate(float64(screenWidth), float64(screenHeight))
-	screen.DrawImage(vineCorner, brOp)
+	screen.DrawImage(t.BorderCorner, brOp)
 
 	// Bottom-left (rotate -90°)
 	blOp := &ebiten.DrawImageOptions{}
 	blOp.GeoM.Scale(scale, scale)
 	blOp.GeoM.Rotate(-math.Pi / 2)
 	blOp.GeoM.Translate(0, float64(screenHeight))
-	screen.DrawImage(vineCorner, blOp)
+	screen.DrawImage(t.BorderCorner, blOp)
 }
 
-func DrawFood(screen *ebiten.Image, pos image.Point, cellSize int) {
-	tileW := appleSprite.Bounds().Dx()
-	tileH := appleSprite.Bounds().Dy()
+// DrawHUDBar fills the HUD area with a darkened background tile and a border along its bottom edge.
+func (t *Theme) DrawHUDBar(screen *ebiten.Image, width, height int, cellSize int) {
+	tileW, tileH := t.Background.Bounds().Dx(), t.Background.Bounds().Dy()
+	for y := 0; y < height; y += cellSize {
+		for x := 0; x < width; x += cellSize {
+			op := &ebiten.DrawImageOptions{}
+			op.GeoM.Scale(float64(cellSize)/float64(tileW), float64(cellSize)/float64(tileH))
+			op.GeoM.Translate(float64(x), float64(y))
+			op.ColorScale.Scale(0.45, 0.45, 0.45, 1)
+			screen.DrawImage(t.Background, op)
+		}
+	}
+
+	// Half-height vine strip, rotated like the board's top border.
+	scale := float64(cellSize) / float64(t.BorderSide.Bounds().Dy()) / 2
+	for x := 0; x < width; x += cellSize / 2 {
+		op := &ebiten.DrawImageOptions{}
+		op.GeoM.Scale(scale, scale)
+		op.GeoM.Rotate(math.Pi / 2)
+		op.GeoM.Translate(float64(x+cellSize/2), float64(height-cellSize/2))
+		screen.DrawImage(t.BorderSide, op)
+	}
+}
+
+// DrawFood draws the sprite for the given food kind at a grid position.
+func (t *Theme) DrawFood(screen *ebiten.Image, kind string, pos image.Point, cellSize int) {
+	drawCellSprite(screen, t.Food[kind], pos, cellSize)
+}
+
+// DrawPowerUp draws the sprite for the given power-up kind at a grid position.
+func (t *Theme) DrawPowerUp(screen *ebiten.Image, kind string, pos image.Point, cellSize int) {
+	drawCellSprite(screen, t.PowerUps[kind], pos, cellSize)
+}
+
+// DrawMover draws the sprite for the given enemy kind at a grid position.
+func (t *Theme) DrawMover(screen *ebiten.Image, kind string, pos image.Point, cellSize int) {
+	drawCellSprite(screen, t.Movers[kind], pos, cellSize)
+}
+
+// DrawPortal draws one end of a portal in the given color. Themes without a portal
+// tile get a colored ring.
+func (t *Theme) DrawPortal(screen *ebiten.Image, pos image.Point, cellSize int, c color.RGBA) {
+	if t.Portal == nil {
+		half := float32(cellSize) / 2
+		cx, cy := float32(pos.X*cellSize)+half, float32(pos.Y*cellSize)+half
+		vector.StrokeCircle(screen, cx, cy, half*0.8, half*0.3, c, true)
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(cellSize)/float64(t.Portal.Bounds().Dx()), float64(cellSize)/float64(t.Portal.Bounds().Dy()))
+	op.GeoM.Translate(float64(pos.X*cellSize), float64(pos.Y*cellSize))
+	op.ColorScale.ScaleWithColor(c)
+	screen.DrawImage(t.Portal, op)
+}
+
+// DrawHazard draws an obstacle at a grid position with the given opacity. Themes
+// without a hazard tile get a dark block in the palette's background color.
+func (t *Theme) DrawHazard(screen *ebiten.Image, pos image.Point, cellSize int, alpha float32) {
+	if t.Hazard == nil {
+		c := t.Palette.Background
+		x, y := float32(pos.X*cellSize), float32(pos.Y*cellSize)
+		vector.DrawFilledRect(screen, x, y, float32(cellSize), float32(cellSize), color.RGBA{R: uint8(float32(c.R) * alpha), G: uint8(float32(c.G) * alpha), B: uint8(float32(c.B) * alpha), A: uint8(255 * alpha)}, false)
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Scale(float64(cellSize)/float64(t.Hazard.Bounds().Dx()), float64(cellSize)/float64(t.Hazard.Bounds().Dy()))
+	op.GeoM.Translate(float64(pos.X*cellSize), float64(pos.Y*cellSize))
+	op.ColorScale.ScaleAlpha(alpha)
+	screen.DrawImage(t.Hazard, op)
+}
+
+// drawCellSprite scales a sprite to fill one grid cell. Missing sprites are skipped.
+func drawCellSprite(screen, sprite *ebiten.Image, pos image.Point, cellSize int) {
+	if sprite == nil {
+		return
+	}
+	tileW := sprite.Bounds().Dx()
+	tileH := sprite.Bounds().Dy()
 	scaleX := float64(cellSize) / float64(tileW)
 	scaleY := float64(cellSize) / float64(tileH)
 
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Scale(scaleX, scaleY)
 	op.GeoM.Translate(float64(pos.X*cellSize), float64(pos.Y*cellSize))
-	screen.DrawImage(appleSprite, op)
+	screen.DrawImage(sprite, op)
 }
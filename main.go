@@ -1,34 +1,65 @@
 package main
 
 import (
+	"fmt"
 	"github.com/hajimehoshi/ebiten/v2"
 	"image"
 	"log"
+	"os"
 	"snakeGame/game/core"
 	"snakeGame/game/ui"
 )
 
 func main() {
 	// Define the game board dimensions in grid cells.
-	gridWidth, gridHeight := 20, 20       // 20x20 grid cells
-	cellSize := 16                        // each cell is 16 pixels square
-	screenWidth := gridWidth * cellSize   // internal pixel width
-	screenHeight := gridHeight * cellSize // internal pixel height
+	gridWidth, gridHeight := 20, 20                 // 20x20 grid cells
+	cellSize := 16                                  // each cell is 16 pixels square
+	screenWidth := gridWidth * cellSize             // internal pixel width
+	hudHeight := core.HUDHeight(cellSize)           // status bar above the board
+	screenHeight := gridHeight*cellSize + hudHeight // internal pixel height
 	start := image.Pt(gridWidth/2, gridHeight/2)
 
+	// "snakeGame verify <file>" re-simulates an exported daily challenge result.
+	if len(os.Args) > 1 && os.Args[1] == "verify" {
+		os.Exit(verify(os.Args[2:]))
+	}
+
 	// Set up the window for desktop. We double the pixel dimensions for a retro-scaled look
 	ebiten.SetWindowSize(screenWidth*2, screenHeight*2)
+	ebiten.SetWindowResizingMode(ebiten.WindowResizingModeEnabled)
 	ebiten.SetWindowTitle("Snake Game")
+	ebiten.SetWindowClosingHandled(true) // lets the game save a run in progress before closing
 
-	// Initialize the Game instance (from our game package).
-	g := core.NewGame(start, gridWidth, gridHeight, cellSize)
-
-	if err := ui.LoadTheme(); err != nil {
+	// Load every installed theme manifest.
+	themes, err := ui.LoadThemes("game/ui/themes")
+	if err != nil {
 		log.Fatal(err)
 	}
 
+	// Initialize the Game instance (from our game package).
+	g := core.NewGame(start, gridWidth, gridHeight, cellSize, themes)
+
 	// Start the game loop. Ebiten will call g.Update, g.Draw, g.Layout appropriately.
 	if err := ebiten.RunGame(g); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// verify checks each daily result file given and returns the process exit code.
+func verify(paths []string) int {
+	if len(paths) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: snakeGame verify <daily_result.json>...")
+		return 2
+	}
+	code := 0
+	for _, path := range paths {
+		result, err := core.VerifyDailyResult(path)
+		if err != nil {
+			fmt.Printf("%s: FAILED: %v\n", path, err)
+			code = 1
+			continue
+		}
+		fmt.Printf("%s: OK: %s score %d in %d moves\n", path, result.Date, result.Score, result.Ticks)
+	}
+	return code
+}